@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/configs"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database/migrations"
+)
+
+const usage = "usage: migrate up|down|status|to <version>"
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	config, err := configs.LoadConfig(".")
+	if err != nil {
+		panic(err)
+	}
+	db, err := database.NewConnection(database.ConnectionConfig{
+		Driver:          config.DBDriver,
+		Host:            config.DBHost,
+		Port:            config.DBPort,
+		User:            config.DBUser,
+		Password:        config.DBPassword,
+		Name:            config.DBName,
+		SSLMode:         config.DBSSLMode,
+		MaxOpenConns:    config.DBMaxOpenConns,
+		MaxIdleConns:    config.DBMaxIdleConns,
+		ConnMaxLifetime: time.Second * time.Duration(config.DBConnMaxLifetime),
+		PingRetries:     config.DBPingRetries,
+	})
+	if err != nil {
+		panic(err)
+	}
+	migrator := migrations.NewMigrator(db)
+
+	switch os.Args[1] {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down()
+	case "to":
+		if len(os.Args) < 3 {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(2)
+		}
+		var version uint64
+		version, err = strconv.ParseUint(os.Args[2], 10, 32)
+		if err == nil {
+			err = migrator.To(uint(version))
+		}
+	case "status":
+		err = printStatus(migrator)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func printStatus(migrator *migrations.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%04d  %-40s  %s\n", status.Version, status.Name, appliedAt)
+	}
+	return nil
+}
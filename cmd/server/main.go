@@ -1,18 +1,24 @@
 package main
 
 import (
+	"errors"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/configs"
 	_ "github.com/felipedias-dev/fullcycle-go-expert-basic-api/docs"
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database/migrations"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/storage"
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/webserver/handlers"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/webserver/middlewares"
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/jwtauth"
 	httpSwagger "github.com/swaggo/http-swagger"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -32,38 +38,282 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	db, err := gorm.Open(sqlite.Open("test.db"), &gorm.Config{})
+	db, err := database.NewConnection(database.ConnectionConfig{
+		Driver:          config.DBDriver,
+		Host:            config.DBHost,
+		Port:            config.DBPort,
+		User:            config.DBUser,
+		Password:        config.DBPassword,
+		Name:            config.DBName,
+		SSLMode:         config.DBSSLMode,
+		MaxOpenConns:    config.DBMaxOpenConns,
+		MaxIdleConns:    config.DBMaxIdleConns,
+		ConnMaxLifetime: time.Second * time.Duration(config.DBConnMaxLifetime),
+		PingRetries:     config.DBPingRetries,
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = migrations.NewMigrator(db).Check()
+	if err != nil {
+		panic(err)
+	}
+
+	trustedProxies, err := middlewares.ParseTrustedProxies(config.TrustedProxies)
 	if err != nil {
 		panic(err)
 	}
-	db.AutoMigrate(&entity.Product{}, &entity.User{})
 
 	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middlewares.RealIP(trustedProxies))
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.WithValue("jwt", config.TokenAuthKey))
 	r.Use(middleware.WithValue("jwtExpiration", config.JWTExpiration))
+	r.Use(middleware.WithValue("refreshExpiration", config.RefreshExpiration))
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		handlers.WriteProblem(w, r, http.StatusNotFound, "not_found", "no route matches "+r.URL.Path)
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		handlers.WriteProblem(w, r, http.StatusMethodNotAllowed, "method_not_allowed", r.Method+" is not allowed on "+r.URL.Path)
+	})
+
+	auditDB := database.NewAudit(db)
+	auditHandler := handlers.NewAuditHandler(auditDB)
+	store := database.NewStore(db)
+	if config.AdminEmail != "" {
+		if err := promoteAdmin(store, config.AdminEmail); err != nil {
+			panic(err)
+		}
+	}
+
+	exchangeRateDB := database.NewExchangeRateCache(database.NewExchangeRate(db), 5*time.Minute)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateDB)
+
+	var imageStorage storage.Storage = storage.NewLocal(config.ImageDir, config.ImageBaseURL)
+	if config.ImageStorage == "s3" {
+		imageStorage = storage.NewS3(config.S3Endpoint, config.S3Region, config.S3Bucket,
+			config.S3AccessKey, config.S3SecretKey, config.S3PublicURL)
+	}
 
 	productDB := database.NewProduct(db)
-	productHandler := handlers.NewProductHandler(productDB)
+	productHandler := handlers.NewProductHandler(productDB, exchangeRateDB, store, imageStorage, []byte(config.CursorSecret))
+
+	warehouseHandler := handlers.NewWarehouseHandler(database.NewWarehouse(db), store)
+	stockDB := database.NewStock(db)
+	stockHandler := handlers.NewStockHandler(stockDB, productDB)
+	go releaseExpiredReservations(stockDB, time.Minute)
+
+	variantHandler := handlers.NewVariantHandler(database.NewVariant(db), productDB, store)
+
+	imageHandler := handlers.NewProductImageHandler(database.NewProductImage(db), productDB, store, imageStorage, config.ImageMaxBytes)
+
+	cartHandler := handlers.NewCartHandler(database.NewCart(db), productDB)
+
+	categoryDB := database.NewCategory(db)
+	categoryHandler := handlers.NewCategoryHandler(categoryDB, productDB, store)
+
+	revokedTokenStore := database.NewRevokedToken(db)
+	revokedTokenDB := database.NewRevokedTokenCache(revokedTokenStore, 10000, 30*time.Second)
+	go deleteExpiredRevokedTokens(revokedTokenStore, time.Hour)
 
 	r.Route("/products", func(r chi.Router) {
 		r.Use(jwtauth.Verifier(config.TokenAuthKey))
-		r.Use(jwtauth.Authenticator)
-		r.Post("/", productHandler.CreateProduct)
-		r.Get("/", productHandler.GetProducts)
-		r.Get("/{id}", productHandler.GetProduct)
-		r.Put("/{id}", productHandler.UpdateProduct)
-		r.Delete("/{id}", productHandler.DeleteProduct)
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireRole(entity.RoleViewer, entity.RoleEditor, entity.RoleAdmin))
+			r.Get("/", productHandler.GetProducts)
+			r.Get("/{id}", productHandler.GetProduct)
+			r.Get("/{id}/prices", productHandler.GetPriceHistory)
+			r.Get("/{id}/categories", categoryHandler.GetProductCategories)
+			r.Get("/{id}/stock", stockHandler.GetStock)
+			r.Get("/{id}/availability", stockHandler.GetAvailability)
+			r.Get("/{id}/variants", variantHandler.GetVariants)
+			r.Get("/{id}/variants/{variantID}", variantHandler.GetVariant)
+			r.Get("/{id}/images", imageHandler.GetImages)
+			r.Post("/{id}/reservations", stockHandler.CreateReservation)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireRole(entity.RoleEditor, entity.RoleAdmin))
+			r.Post("/", productHandler.CreateProduct)
+			r.Put("/{id}", productHandler.UpdateProduct)
+			r.Patch("/{id}", productHandler.PatchProduct)
+			r.Delete("/{id}", productHandler.DeleteProduct)
+			r.Get("/trash", productHandler.GetTrash)
+			r.Post("/{id}/restore", productHandler.RestoreProduct)
+			r.Put("/{id}/categories", categoryHandler.SetProductCategories)
+			r.Post("/{id}/tags", productHandler.AddProductTags)
+			r.Delete("/{id}/tags", productHandler.RemoveProductTags)
+			r.Get("/{id}/stock/adjustments", stockHandler.GetAdjustments)
+			r.Post("/{id}/stock/adjustments", stockHandler.CreateAdjustment)
+			r.Get("/{id}/stock/movements", stockHandler.GetMovements)
+			r.Post("/{id}/stock/transfers", stockHandler.CreateTransfer)
+			r.Post("/{id}/variants", variantHandler.CreateVariant)
+			r.Put("/{id}/variants/{variantID}", variantHandler.UpdateVariant)
+			r.Delete("/{id}/variants/{variantID}", variantHandler.DeleteVariant)
+			r.Post("/{id}/images", imageHandler.UploadImage)
+			r.Delete("/{id}/images/{imageID}", imageHandler.DeleteImage)
+		})
+	})
+
+	r.Route("/warehouses", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(config.TokenAuthKey))
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireRole(entity.RoleViewer, entity.RoleEditor, entity.RoleAdmin))
+			r.Get("/", warehouseHandler.GetWarehouses)
+			r.Get("/{id}", warehouseHandler.GetWarehouse)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireRole(entity.RoleAdmin))
+			r.Post("/", warehouseHandler.CreateWarehouse)
+			r.Put("/{id}", warehouseHandler.UpdateWarehouse)
+		})
+	})
+
+	r.Route("/reservations", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(config.TokenAuthKey))
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.Use(middlewares.RequireRole(entity.RoleViewer, entity.RoleEditor, entity.RoleAdmin))
+		r.Get("/{id}", stockHandler.GetReservation)
+		r.Post("/{id}/commit", stockHandler.CommitReservation)
+		r.Post("/{id}/release", stockHandler.ReleaseReservation)
+	})
+
+	r.Route("/cart", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(config.TokenAuthKey))
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.Use(middlewares.RequireRole(entity.RoleViewer, entity.RoleEditor, entity.RoleAdmin))
+		r.Get("/", cartHandler.GetCart)
+		r.Delete("/", cartHandler.ClearCart)
+		r.Post("/items", cartHandler.AddItem)
+		r.Put("/items/{productID}", cartHandler.UpdateItem)
+		r.Delete("/items/{productID}", cartHandler.RemoveItem)
+	})
+
+	r.Route("/tags", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(config.TokenAuthKey))
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.Use(middlewares.RequireRole(entity.RoleViewer, entity.RoleEditor, entity.RoleAdmin))
+		r.Get("/", productHandler.GetTags)
+	})
+
+	r.Route("/categories", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(config.TokenAuthKey))
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireRole(entity.RoleViewer, entity.RoleEditor, entity.RoleAdmin))
+			r.Get("/", categoryHandler.GetCategories)
+			r.Get("/{id}", categoryHandler.GetCategory)
+		})
+		r.Group(func(r chi.Router) {
+			r.Use(middlewares.RequireRole(entity.RoleEditor, entity.RoleAdmin))
+			r.Post("/", categoryHandler.CreateCategory)
+			r.Put("/{id}", categoryHandler.UpdateCategory)
+			r.Delete("/{id}", categoryHandler.DeleteCategory)
+		})
+	})
+
+	r.Route("/exchange-rates", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(config.TokenAuthKey))
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.With(middlewares.RequireRole(entity.RoleViewer, entity.RoleEditor, entity.RoleAdmin)).Get("/", exchangeRateHandler.GetExchangeRates)
+		r.With(middlewares.RequireRole(entity.RoleAdmin)).Put("/{base}/{quote}", exchangeRateHandler.SaveExchangeRate)
+	})
+
+	r.Route("/audit", func(r chi.Router) {
+		r.Use(jwtauth.Verifier(config.TokenAuthKey))
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.Use(middlewares.RequireRole(entity.RoleAdmin))
+		r.Get("/", auditHandler.GetAudit)
 	})
 
 	userDB := database.NewUser(db)
-	userHandler := handlers.NewUserHandler(userDB)
+	refreshTokenDB := database.NewRefreshToken(db)
+	userHandler := handlers.NewUserHandler(userDB, refreshTokenDB, revokedTokenDB, store)
 
 	r.Post("/users", userHandler.CreateUser)
 	r.Post("/users/auth", userHandler.GetJWTHandler)
+	r.Post("/users/auth/refresh", userHandler.RefreshJWTHandler)
+	r.Group(func(r chi.Router) {
+		r.Use(jwtauth.Verifier(config.TokenAuthKey))
+		r.Use(middlewares.Authenticator)
+		r.Use(middlewares.RejectRevoked(revokedTokenDB))
+		r.Post("/users/logout", userHandler.Logout)
+		r.With(middlewares.RequireRole(entity.RoleAdmin)).Put("/users/{id}/role", userHandler.UpdateUserRole)
+	})
+
+	if config.ImageStorage != "s3" {
+		r.Get("/images/*", serveImages(config.ImageDir))
+	}
 
 	r.Get("/docs/*", httpSwagger.Handler(httpSwagger.URL("http://localhost:8000/docs/doc.json")))
 
 	http.ListenAndServe(":8000", r)
 }
+
+// promoteAdmin gives the admin role to the user registered with email, so
+// that a new installation has someone who can assign roles. Signing up only
+// grants the viewer role; when nobody has that email yet, the promotion
+// waits for the next start.
+func promoteAdmin(store *database.Store, email string) error {
+	return store.Transaction(func(tx database.Repositories) error {
+		user, err := tx.UserDB.FindByEmail(email)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("ADMIN_EMAIL %s is not registered yet; sign up and restart the server to promote it", email)
+			return nil
+		}
+		if err != nil || user.Role == entity.RoleAdmin {
+			return err
+		}
+		if err := tx.UserDB.UpdateRole(user.ID.String(), entity.RoleAdmin); err != nil {
+			return err
+		}
+		log.Printf("promoted %s to %s", email, entity.RoleAdmin)
+		changes := entity.AuditChanges{"role": {From: user.Role, To: entity.RoleAdmin}}
+		return tx.AuditDB.Record(entity.NewAuditEntry(nil, entity.AuditActionUpdate, "user", user.ID.String(), changes))
+	})
+}
+
+// releaseExpiredReservations returns the stock of expired reservations every
+// interval. Reservations are also released lazily when the same product is
+// reserved again, so this only matters for products nobody is buying.
+func releaseExpiredReservations(stockDB *database.Stock, interval time.Duration) {
+	for range time.Tick(interval) {
+		if _, err := stockDB.ReleaseExpired(); err != nil {
+			log.Printf("releasing expired reservations: %v", err)
+		}
+	}
+}
+
+// deleteExpiredRevokedTokens keeps the denylist from growing without bound
+// by dropping tokens that have expired every interval.
+func deleteExpiredRevokedTokens(revokedTokenDB *database.RevokedToken, interval time.Duration) {
+	for range time.Tick(interval) {
+		if err := revokedTokenDB.DeleteExpired(); err != nil {
+			log.Printf("deleting expired revoked tokens: %v", err)
+		}
+	}
+}
+
+// serveImages serves the images kept by the local storage, without listing
+// directories. The files are public, like the URLs of S3 storage.
+func serveImages(dir string) http.HandlerFunc {
+	files := http.StripPrefix("/images/", http.FileServer(http.Dir(dir)))
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			handlers.WriteProblem(w, r, http.StatusNotFound, "not_found", "no route matches "+r.URL.Path)
+			return
+		}
+		files.ServeHTTP(w, r)
+	}
+}
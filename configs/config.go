@@ -6,16 +6,35 @@ import (
 )
 
 type config struct {
-	DBDriver      string `mapstructure:"DB_DRIVER"`
-	DBHost        string `mapstructure:"DB_HOST"`
-	DBPort        string `mapstructure:"DB_PORT"`
-	DBUser        string `mapstructure:"DB_USER"`
-	DBPassword    string `mapstructure:"DB_PASSWORD"`
-	DBName        string `mapstructure:"DB_NAME"`
-	WebServerPort string `mapstructure:"WEB_SERVER_PORT"`
-	JWTSecret     string `mapstructure:"JWT_SECRET"`
-	JWTExpiration int    `mapstructure:"JWT_EXPIRATION"`
-	TokenAuthKey  *jwtauth.JWTAuth
+	DBDriver          string `mapstructure:"DB_DRIVER"`
+	DBHost            string `mapstructure:"DB_HOST"`
+	DBPort            string `mapstructure:"DB_PORT"`
+	DBUser            string `mapstructure:"DB_USER"`
+	DBPassword        string `mapstructure:"DB_PASSWORD"`
+	DBName            string `mapstructure:"DB_NAME"`
+	DBSSLMode         string `mapstructure:"DB_SSLMODE"`
+	DBMaxOpenConns    int    `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns    int    `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime int    `mapstructure:"DB_CONN_MAX_LIFETIME"`
+	DBPingRetries     int    `mapstructure:"DB_PING_RETRIES"`
+	WebServerPort     string `mapstructure:"WEB_SERVER_PORT"`
+	TrustedProxies    string `mapstructure:"TRUSTED_PROXIES"`
+	JWTSecret         string `mapstructure:"JWT_SECRET"`
+	JWTExpiration     int    `mapstructure:"JWT_EXPIRATION"`
+	RefreshExpiration int    `mapstructure:"REFRESH_EXPIRATION"`
+	AdminEmail        string `mapstructure:"ADMIN_EMAIL"`
+	CursorSecret      string `mapstructure:"CURSOR_SECRET"`
+	ImageStorage      string `mapstructure:"IMAGE_STORAGE"`
+	ImageDir          string `mapstructure:"IMAGE_DIR"`
+	ImageBaseURL      string `mapstructure:"IMAGE_BASE_URL"`
+	ImageMaxBytes     int64  `mapstructure:"IMAGE_MAX_BYTES"`
+	S3Endpoint        string `mapstructure:"S3_ENDPOINT"`
+	S3Region          string `mapstructure:"S3_REGION"`
+	S3Bucket          string `mapstructure:"S3_BUCKET"`
+	S3AccessKey       string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey       string `mapstructure:"S3_SECRET_KEY"`
+	S3PublicURL       string `mapstructure:"S3_PUBLIC_URL"`
+	TokenAuthKey      *jwtauth.JWTAuth
 }
 
 func LoadConfig(path string) (*config, error) {
@@ -1,13 +1,62 @@
 package dto
 
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+// PriceInput is a price as clients send it: {"amount": "12.34", "currency":
+// "BRL"}, or a bare integer of whole units in the default currency for
+// clients written before prices had a currency.
+type PriceInput struct {
+	Amount   string `json:"amount" example:"12.34"`
+	Currency string `json:"currency,omitempty" example:"BRL"`
+}
+
+func (p *PriceInput) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && (data[0] == '-' || (data[0] >= '0' && data[0] <= '9')) {
+		var units int64
+		if err := json.Unmarshal(data, &units); err != nil {
+			return err
+		}
+		*p = PriceInput{Amount: strconv.FormatInt(units, 10)}
+		return nil
+	}
+	type price PriceInput
+	return json.Unmarshal(data, (*price)(p))
+}
+
+// Money parses the price, falling back to the default currency.
+func (p PriceInput) Money() (pkgEntity.Money, error) {
+	currency := p.Currency
+	if currency == "" {
+		currency = pkgEntity.DefaultCurrency
+	}
+	return pkgEntity.ParseMoney(p.Amount, currency)
+}
+
 type CreateProductInput struct {
-	Name  string `json:"name"`
-	Price int    `json:"price"`
+	Name  string     `json:"name"`
+	Price PriceInput `json:"price"`
+	Tags  []string   `json:"tags,omitempty"`
 }
 
 type UpdateProductInput struct {
-	Name  string `json:"name"`
-	Price int    `json:"price"`
+	Name  string     `json:"name"`
+	Price PriceInput `json:"price"`
+}
+
+// PatchProductDocument is the view of a product that PATCH requests are
+// applied to; any other field in the patched result is rejected.
+type PatchProductDocument struct {
+	Name  string     `json:"name"`
+	Price PriceInput `json:"price"`
 }
 
 type CreateUserInput struct {
@@ -22,5 +71,185 @@ type GetJWTInput struct {
 }
 
 type GetJWTOutput struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshJWTInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type UpdateUserRoleInput struct {
+	Role string `json:"role"`
+}
+
+type MoneyOutput struct {
+	Amount   string `json:"amount" example:"12.34"`
+	Currency string `json:"currency" example:"BRL"`
+}
+
+func NewMoneyOutput(money pkgEntity.Money) *MoneyOutput {
+	return &MoneyOutput{Amount: money.Decimal(), Currency: money.Currency}
+}
+
+// ProductOutput is a product as the API returns it. DisplayPrice and
+// ExchangeRate are only set when the client asked for another currency; the
+// stored Price is never converted.
+type ProductOutput struct {
+	entity.Product
+	DisplayPrice *MoneyOutput         `json:"display_price,omitempty"`
+	ExchangeRate *entity.ExchangeRate `json:"exchange_rate,omitempty"`
+}
+
+type ProductListOutput struct {
+	Items      []ProductOutput `json:"items"`
+	Page       int             `json:"page,omitempty"`
+	Limit      int             `json:"limit"`
+	Total      int64           `json:"total"`
+	TotalPages int             `json:"total_pages"`
+	Next       string          `json:"next,omitempty"`
+	Prev       string          `json:"prev,omitempty"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type SaveExchangeRateInput struct {
+	Rate string `json:"rate" example:"0.1834"`
+}
+
+type AuditListOutput struct {
+	Items      []entity.AuditEntry `json:"items"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	Total      int64               `json:"total"`
+	TotalPages int                 `json:"total_pages"`
+	Next       string              `json:"next,omitempty"`
+	Prev       string              `json:"prev,omitempty"`
+}
+
+type CategoryInput struct {
+	Name     string  `json:"name"`
+	ParentID *string `json:"parent_id" format:"uuid"`
+}
+
+// CategoryNode is a category with its subcategories, as GET /categories
+// returns the tree.
+type CategoryNode struct {
+	entity.Category
+	Children []CategoryNode `json:"children"`
+}
+
+// NewCategoryTree nests categories under their parents. A category whose
+// parent is not in the list becomes a root; siblings keep the list's order.
+func NewCategoryTree(categories []entity.Category) []CategoryNode {
+	known := map[pkgEntity.ID]bool{}
+	for _, c := range categories {
+		known[c.ID] = true
+	}
+	children := map[pkgEntity.ID][]entity.Category{}
+	var roots []entity.Category
+	for _, c := range categories {
+		if c.ParentID != nil && known[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+	var build func([]entity.Category) []CategoryNode
+	build = func(level []entity.Category) []CategoryNode {
+		nodes := make([]CategoryNode, len(level))
+		for i, c := range level {
+			nodes[i] = CategoryNode{Category: c, Children: build(children[c.ID])}
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+type SetProductCategoriesInput struct {
+	CategoryIDs []string `json:"category_ids"`
+}
+
+type ProductTagsInput struct {
+	Tags []string `json:"tags" example:"sale"`
+}
+
+type StockAdjustmentInput struct {
+	Delta  int64  `json:"delta" example:"-2"`
+	Reason string `json:"reason" enums:"received,returned,damaged,lost,sold,correction"`
+	Note   string `json:"note,omitempty"`
+	// WarehouseID also changes the quantity held at that warehouse.
+	WarehouseID *string `json:"warehouse_id,omitempty" format:"uuid"`
+}
+
+type ReservationInput struct {
+	Quantity int64 `json:"quantity" example:"1"`
+	// TTLSeconds defaults to 15 minutes when omitted.
+	TTLSeconds int64 `json:"ttl_seconds,omitempty" example:"900"`
+}
+
+// TTL is the requested time to live, or the default one.
+func (i ReservationInput) TTL() time.Duration {
+	if i.TTLSeconds == 0 {
+		return entity.DefaultReservationTTL
+	}
+	return time.Duration(i.TTLSeconds) * time.Second
+}
+
+type StockOutput struct {
+	ProductID string `json:"product_id"`
+	Available int64  `json:"available"`
+	Reserved  int64  `json:"reserved"`
+}
+
+// AvailabilityOutput shows a product's stock across warehouses. OnHand sums
+// the warehouse quantities, which still include reserved units; stock added
+// without a warehouse only counts towards Available.
+type AvailabilityOutput struct {
+	ProductID string                 `json:"product_id"`
+	Available int64                  `json:"available"`
+	Reserved  int64                  `json:"reserved"`
+	OnHand    int64                  `json:"on_hand"`
+	Locations []entity.StockLocation `json:"locations"`
+}
+
+type WarehouseInput struct {
+	Code string `json:"code" example:"SP-01"`
+	Name string `json:"name" example:"São Paulo"`
+}
+
+type StockTransferInput struct {
+	FromWarehouseID string `json:"from_warehouse_id" format:"uuid"`
+	ToWarehouseID   string `json:"to_warehouse_id" format:"uuid"`
+	Quantity        int64  `json:"quantity" example:"1"`
+}
+
+// VariantInput describes a variant. Price is optional; without it the
+// variant sells for the product's price.
+type VariantInput struct {
+	SKU     string            `json:"sku" example:"SHIRT-M-RED"`
+	Options map[string]string `json:"options"`
+	Price   *PriceInput       `json:"price,omitempty"`
+}
+
+// VariantOutput is a variant with the price it actually sells for.
+type VariantOutput struct {
+	entity.Variant
+	EffectivePrice *MoneyOutput `json:"effective_price"`
+}
+
+func NewVariantOutput(variant entity.Variant, product *entity.Product) VariantOutput {
+	return VariantOutput{Variant: variant, EffectivePrice: NewMoneyOutput(variant.EffectivePrice(product))}
+}
+
+type CartItemInput struct {
+	ProductID string `json:"product_id" format:"uuid"`
+	Quantity  int64  `json:"quantity" example:"1"`
+}
+
+type CartItemQuantityInput struct {
+	Quantity int64 `json:"quantity" example:"2"`
 }
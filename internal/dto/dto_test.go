@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceInput_UnmarshalJSON(t *testing.T) {
+	var input CreateProductInput
+	err := json.Unmarshal([]byte(`{"name": "Product 1", "price": {"amount": "12.34", "currency": "USD"}}`), &input)
+	assert.Nil(t, err)
+	money, err := input.Price.Money()
+	assert.Nil(t, err)
+	assert.Equal(t, pkgEntity.Money{Amount: 1234, Currency: "USD"}, money)
+
+	err = json.Unmarshal([]byte(`{"name": "Product 1", "price": 10}`), &input)
+	assert.Nil(t, err)
+	money, err = input.Price.Money()
+	assert.Nil(t, err)
+	assert.Equal(t, pkgEntity.Money{Amount: 1000, Currency: pkgEntity.DefaultCurrency}, money)
+
+	err = json.Unmarshal([]byte(`{"name": "Product 1", "price": 10.5}`), &input)
+	assert.NotNil(t, err)
+}
+
+func TestNewCategoryTree(t *testing.T) {
+	clothing, _ := entity.NewCategory("Clothing", nil)
+	shirts, _ := entity.NewCategory("Shirts", &clothing.ID)
+	tees, _ := entity.NewCategory("T-Shirts", &shirts.ID)
+	missing := pkgEntity.NewID()
+	stray, _ := entity.NewCategory("Stray", &missing)
+
+	tree := NewCategoryTree([]entity.Category{*clothing, *shirts, *stray, *tees})
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "Clothing", tree[0].Name)
+	assert.Equal(t, "Stray", tree[1].Name)
+	assert.Equal(t, "Shirts", tree[0].Children[0].Name)
+	assert.Equal(t, "T-Shirts", tree[0].Children[0].Children[0].Name)
+	assert.Empty(t, tree[0].Children[0].Children[0].Children)
+}
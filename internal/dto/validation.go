@@ -0,0 +1,294 @@
+package dto
+
+import (
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+const (
+	productNameMaxLength   = 100
+	categoryNameMaxLength  = 100
+	userNameMinLength      = 2
+	userNameMaxLength      = 100
+	emailMaxLength         = 254
+	stockNoteMaxLength     = 255
+	warehouseNameMaxLength = 100
+	passwordMinLength      = 8
+	// bcrypt ignores everything after the 72nd byte.
+	passwordMaxLength = 72
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors lists every rule an input breaks, so clients can report
+// all of them at once instead of fixing one field per request.
+type ValidationErrors []FieldError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, len(v))
+	for i, e := range v {
+		messages[i] = e.Field + ": " + e.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (v *ValidationErrors) add(field, code, message string) {
+	*v = append(*v, FieldError{Field: field, Code: code, Message: message})
+}
+
+func (v ValidationErrors) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (v *ValidationErrors) checkName(field, value string, min, max int) {
+	length := utf8.RuneCountInString(strings.TrimSpace(value))
+	switch {
+	case length == 0:
+		v.add(field, "required", field+" is required")
+	case length < min:
+		v.add(field, "too_short", fmt.Sprintf("%s must have at least %d characters", field, min))
+	case length > max:
+		v.add(field, "too_long", fmt.Sprintf("%s must have at most %d characters", field, max))
+	}
+}
+
+func (v *ValidationErrors) checkPrice(field string, value PriceInput) {
+	if value.Amount == "" {
+		v.add(field, "required", field+" is required")
+		return
+	}
+	money, err := value.Money()
+	switch {
+	case err == pkgEntity.ErrInvalidCurrency:
+		v.add(field+".currency", "invalid_currency", field+".currency must be a supported ISO 4217 code")
+	case err != nil:
+		v.add(field+".amount", "invalid_format", field+".amount must be a decimal number with at most the currency's number of decimal digits")
+	case money.IsZero():
+		v.add(field, "required", field+" is required")
+	case money.IsNegative():
+		v.add(field, "must_be_positive", field+" must be greater than zero")
+	}
+}
+
+func (v *ValidationErrors) checkTags(field string, tags []string) {
+	for n, tag := range tags {
+		if _, err := entity.NormalizeTag(tag); err != nil {
+			name := fmt.Sprintf("%s[%d]", field, n)
+			v.add(name, "invalid_format", name+" must have 1 to 50 characters and no commas")
+		}
+	}
+}
+
+func (v *ValidationErrors) checkID(field, value string) {
+	if _, err := pkgEntity.ParseID(value); err != nil {
+		v.add(field, "invalid_format", field+" must be a UUID")
+	}
+}
+
+func (v *ValidationErrors) checkEmail(field, value string) {
+	if value == "" {
+		v.add(field, "required", field+" is required")
+		return
+	}
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value || len(value) > emailMaxLength {
+		v.add(field, "invalid_format", field+" must be a valid email address")
+	}
+}
+
+func (v *ValidationErrors) checkPassword(field, value string) {
+	if value == "" {
+		v.add(field, "required", field+" is required")
+		return
+	}
+	if utf8.RuneCountInString(value) < passwordMinLength {
+		v.add(field, "too_short", fmt.Sprintf("%s must have at least %d characters", field, passwordMinLength))
+	}
+	if len(value) > passwordMaxLength {
+		v.add(field, "too_long", fmt.Sprintf("%s must have at most %d bytes", field, passwordMaxLength))
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range value {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	if !hasLetter || !hasDigit {
+		v.add(field, "weak_password", field+" must contain at least one letter and one digit")
+	}
+}
+
+func (i CreateProductInput) Validate() error {
+	var errs ValidationErrors
+	errs.checkName("name", i.Name, 1, productNameMaxLength)
+	errs.checkPrice("price", i.Price)
+	errs.checkTags("tags", i.Tags)
+	return errs.err()
+}
+
+func (i UpdateProductInput) Validate() error {
+	var errs ValidationErrors
+	errs.checkName("name", i.Name, 1, productNameMaxLength)
+	errs.checkPrice("price", i.Price)
+	return errs.err()
+}
+
+func (i ProductTagsInput) Validate() error {
+	var errs ValidationErrors
+	if len(i.Tags) == 0 {
+		errs.add("tags", "required", "tags is required")
+	}
+	errs.checkTags("tags", i.Tags)
+	return errs.err()
+}
+
+func (i StockAdjustmentInput) Validate() error {
+	var errs ValidationErrors
+	if i.Delta == 0 {
+		errs.add("delta", "required", "delta is required")
+	}
+	if !entity.IsValidStockReason(i.Reason) {
+		errs.add("reason", "invalid_choice", "reason must be one of received, returned, damaged, lost, sold or correction")
+	}
+	if utf8.RuneCountInString(i.Note) > stockNoteMaxLength {
+		errs.add("note", "too_long", fmt.Sprintf("note must have at most %d characters", stockNoteMaxLength))
+	}
+	if i.WarehouseID != nil {
+		errs.checkID("warehouse_id", *i.WarehouseID)
+	}
+	return errs.err()
+}
+
+func (i WarehouseInput) Validate() error {
+	var errs ValidationErrors
+	if !entity.IsValidWarehouseCode(i.Code) {
+		errs.add("code", "invalid_format", "code must have 1 to 16 letters, digits or dashes")
+	}
+	errs.checkName("name", i.Name, 1, warehouseNameMaxLength)
+	return errs.err()
+}
+
+func (i StockTransferInput) Validate() error {
+	var errs ValidationErrors
+	errs.checkID("from_warehouse_id", i.FromWarehouseID)
+	errs.checkID("to_warehouse_id", i.ToWarehouseID)
+	if i.FromWarehouseID != "" && i.FromWarehouseID == i.ToWarehouseID {
+		errs.add("to_warehouse_id", "must_differ", "to_warehouse_id must differ from from_warehouse_id")
+	}
+	if i.Quantity <= 0 {
+		errs.add("quantity", "must_be_positive", "quantity must be greater than zero")
+	}
+	return errs.err()
+}
+
+func (i ReservationInput) Validate() error {
+	var errs ValidationErrors
+	if i.Quantity <= 0 {
+		errs.add("quantity", "must_be_positive", "quantity must be greater than zero")
+	}
+	if ttl := i.TTL(); ttl <= 0 || ttl > entity.MaxReservationTTL {
+		errs.add("ttl_seconds", "out_of_range", fmt.Sprintf("ttl_seconds must be between 1 and %d", int64(entity.MaxReservationTTL/time.Second)))
+	}
+	return errs.err()
+}
+
+func (i CategoryInput) Validate() error {
+	var errs ValidationErrors
+	errs.checkName("name", i.Name, 1, categoryNameMaxLength)
+	if i.ParentID != nil {
+		errs.checkID("parent_id", *i.ParentID)
+	}
+	return errs.err()
+}
+
+func (i SetProductCategoriesInput) Validate() error {
+	var errs ValidationErrors
+	for n, id := range i.CategoryIDs {
+		errs.checkID(fmt.Sprintf("category_ids[%d]", n), id)
+	}
+	return errs.err()
+}
+
+func (i CreateUserInput) Validate() error {
+	var errs ValidationErrors
+	errs.checkName("name", i.Name, userNameMinLength, userNameMaxLength)
+	errs.checkEmail("email", i.Email)
+	errs.checkPassword("password", i.Password)
+	return errs.err()
+}
+
+// Validate only checks that credentials are present and well formed; the
+// password policy is not applied so accounts created before it still log in.
+func (i GetJWTInput) Validate() error {
+	var errs ValidationErrors
+	errs.checkEmail("email", i.Email)
+	if i.Password == "" {
+		errs.add("password", "required", "password is required")
+	}
+	return errs.err()
+}
+
+func (i VariantInput) Validate() error {
+	var errs ValidationErrors
+	switch {
+	case strings.TrimSpace(i.SKU) == "":
+		errs.add("sku", "required", "sku is required")
+	case !entity.IsValidSKU(i.SKU):
+		errs.add("sku", "invalid_format", "sku must have 1 to 64 letters, digits, dots, dashes or underscores")
+	}
+	switch {
+	case len(i.Options) == 0:
+		errs.add("options", "required", "options is required")
+	case len(i.Options) > entity.VariantMaxOptions:
+		errs.add("options", "too_long", fmt.Sprintf("options must have at most %d attributes", entity.VariantMaxOptions))
+	}
+	names := make([]string, 0, len(i.Options))
+	for name := range i.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.TrimSpace(i.Options[name])
+		if !entity.IsValidVariantOption(strings.ToLower(strings.TrimSpace(name)), value) {
+			field := "options." + name
+			errs.add(field, "invalid_format", field+" must have a lowercase name and a value of 1 to 50 characters")
+		}
+	}
+	if i.Price != nil {
+		errs.checkPrice("price", *i.Price)
+	}
+	return errs.err()
+}
+
+func (v *ValidationErrors) checkCartQuantity(field string, quantity int64) {
+	if quantity <= 0 || quantity > entity.MaxCartItemQuantity {
+		v.add(field, "out_of_range", fmt.Sprintf("%s must be between 1 and %d", field, entity.MaxCartItemQuantity))
+	}
+}
+
+func (i CartItemInput) Validate() error {
+	var errs ValidationErrors
+	errs.checkID("product_id", i.ProductID)
+	errs.checkCartQuantity("quantity", i.Quantity)
+	return errs.err()
+}
+
+func (i CartItemQuantityInput) Validate() error {
+	var errs ValidationErrors
+	errs.checkCartQuantity("quantity", i.Quantity)
+	return errs.err()
+}
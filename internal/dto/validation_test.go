@@ -0,0 +1,137 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func codes(err error) map[string]string {
+	result := map[string]string{}
+	if errs, ok := err.(ValidationErrors); ok {
+		for _, e := range errs {
+			result[e.Field] = e.Code
+		}
+	}
+	return result
+}
+
+func TestCreateProductInput_Validate(t *testing.T) {
+	assert.Nil(t, CreateProductInput{Name: "Product 1", Price: PriceInput{Amount: "10"}}.Validate())
+	assert.Nil(t, CreateProductInput{Name: "Product 1", Price: PriceInput{Amount: "12.34", Currency: "USD"}}.Validate())
+
+	err := CreateProductInput{Name: " ", Price: PriceInput{Amount: "-1"}}.Validate()
+	assert.Equal(t, map[string]string{"name": "required", "price": "must_be_positive"}, codes(err))
+
+	err = CreateProductInput{Name: "Product 1", Price: PriceInput{Amount: "0.00"}}.Validate()
+	assert.Equal(t, map[string]string{"price": "required"}, codes(err))
+
+	err = CreateProductInput{Name: "Product 1", Price: PriceInput{Amount: "1.234"}}.Validate()
+	assert.Equal(t, map[string]string{"price.amount": "invalid_format"}, codes(err))
+
+	err = CreateProductInput{Name: "Product 1", Price: PriceInput{Amount: "1.23", Currency: "XYZ"}}.Validate()
+	assert.Equal(t, map[string]string{"price.currency": "invalid_currency"}, codes(err))
+
+	err = UpdateProductInput{Name: strings.Repeat("a", 101)}.Validate()
+	assert.Equal(t, map[string]string{"name": "too_long", "price": "required"}, codes(err))
+}
+
+func TestCreateUserInput_Validate(t *testing.T) {
+	assert.Nil(t, CreateUserInput{Name: "Felipe", Email: "felipe@gmail.com", Password: "secret123"}.Validate())
+
+	err := CreateUserInput{}.Validate()
+	assert.Equal(t, map[string]string{"name": "required", "email": "required", "password": "required"}, codes(err))
+	assert.Len(t, err.(ValidationErrors), 3)
+
+	err = CreateUserInput{Name: "F", Email: "Felipe <felipe@gmail.com>", Password: "password"}.Validate()
+	assert.Equal(t, map[string]string{"name": "too_short", "email": "invalid_format", "password": "weak_password"}, codes(err))
+
+	err = CreateUserInput{Name: "Felipe", Email: "felipe", Password: "a1"}.Validate()
+	assert.Equal(t, map[string]string{"email": "invalid_format", "password": "too_short"}, codes(err))
+}
+
+func TestGetJWTInput_Validate(t *testing.T) {
+	assert.Nil(t, GetJWTInput{Email: "felipe@gmail.com", Password: "123456"}.Validate())
+
+	err := GetJWTInput{Email: "felipe@"}.Validate()
+	assert.Equal(t, map[string]string{"email": "invalid_format", "password": "required"}, codes(err))
+}
+
+func TestCategoryInput_Validate(t *testing.T) {
+	parentID := "d069eb23-c7a5-4c87-ae70-455f687771e8"
+	assert.Nil(t, CategoryInput{Name: "Shirts", ParentID: &parentID}.Validate())
+
+	invalid := "shirts"
+	err := CategoryInput{ParentID: &invalid}.Validate()
+	assert.Equal(t, map[string]string{"name": "required", "parent_id": "invalid_format"}, codes(err))
+
+	err = SetProductCategoriesInput{CategoryIDs: []string{parentID, "1"}}.Validate()
+	assert.Equal(t, map[string]string{"category_ids[1]": "invalid_format"}, codes(err))
+}
+
+func TestProductTagsInput_Validate(t *testing.T) {
+	assert.Nil(t, ProductTagsInput{Tags: []string{"Sale", "summer"}}.Validate())
+
+	err := ProductTagsInput{}.Validate()
+	assert.Equal(t, map[string]string{"tags": "required"}, codes(err))
+
+	err = CreateProductInput{Name: "Product 1", Price: PriceInput{Amount: "10"}, Tags: []string{"sale", "a,b"}}.Validate()
+	assert.Equal(t, map[string]string{"tags[1]": "invalid_format"}, codes(err))
+}
+
+func TestStockAdjustmentInput_Validate(t *testing.T) {
+	assert.Nil(t, StockAdjustmentInput{Delta: -2, Reason: "damaged"}.Validate())
+
+	err := StockAdjustmentInput{Reason: "gift", Note: strings.Repeat("a", 256)}.Validate()
+	assert.Equal(t, map[string]string{"delta": "required", "reason": "invalid_choice", "note": "too_long"}, codes(err))
+}
+
+func TestReservationInput_Validate(t *testing.T) {
+	assert.Nil(t, ReservationInput{Quantity: 1}.Validate())
+	assert.Equal(t, 15*time.Minute, ReservationInput{Quantity: 1}.TTL())
+
+	err := ReservationInput{Quantity: 0, TTLSeconds: 86401}.Validate()
+	assert.Equal(t, map[string]string{"quantity": "must_be_positive", "ttl_seconds": "out_of_range"}, codes(err))
+
+	err = ReservationInput{Quantity: 1, TTLSeconds: -1}.Validate()
+	assert.Equal(t, map[string]string{"ttl_seconds": "out_of_range"}, codes(err))
+}
+
+func TestWarehouseInput_Validate(t *testing.T) {
+	assert.Nil(t, WarehouseInput{Code: "sp-01", Name: "São Paulo"}.Validate())
+
+	err := WarehouseInput{Code: "SP 01"}.Validate()
+	assert.Equal(t, map[string]string{"code": "invalid_format", "name": "required"}, codes(err))
+}
+
+func TestStockTransferInput_Validate(t *testing.T) {
+	from, to := "d069eb23-c7a5-4c87-ae70-455f687771e8", "5b0f6a62-3f4e-4c1d-9d53-2f1d9a9e0c11"
+	assert.Nil(t, StockTransferInput{FromWarehouseID: from, ToWarehouseID: to, Quantity: 2}.Validate())
+
+	err := StockTransferInput{FromWarehouseID: from, ToWarehouseID: from}.Validate()
+	assert.Equal(t, map[string]string{"to_warehouse_id": "must_differ", "quantity": "must_be_positive"}, codes(err))
+}
+
+func TestVariantInput_Validate(t *testing.T) {
+	assert.Nil(t, VariantInput{SKU: "shirt-m", Options: map[string]string{"Size": "M"}}.Validate())
+	assert.Nil(t, VariantInput{SKU: "SHIRT-M", Options: map[string]string{"size": "M"}, Price: &PriceInput{Amount: "19.90"}}.Validate())
+
+	err := VariantInput{Price: &PriceInput{Amount: "-1"}}.Validate()
+	assert.Equal(t, map[string]string{"sku": "required", "options": "required", "price": "must_be_positive"}, codes(err))
+
+	err = VariantInput{SKU: "SHIRT M", Options: map[string]string{"size": "", "1color": "red"}}.Validate()
+	assert.Equal(t, map[string]string{"sku": "invalid_format", "options.size": "invalid_format", "options.1color": "invalid_format"}, codes(err))
+}
+
+func TestCartItemInput_Validate(t *testing.T) {
+	assert.Nil(t, CartItemInput{ProductID: "d069eb23-c7a5-4c87-ae70-455f687771e8", Quantity: 2}.Validate())
+
+	err := CartItemInput{ProductID: "abc", Quantity: 1000}.Validate()
+	assert.Equal(t, map[string]string{"product_id": "invalid_format", "quantity": "out_of_range"}, codes(err))
+
+	assert.Nil(t, CartItemQuantityInput{Quantity: 999}.Validate())
+	err = CartItemQuantityInput{}.Validate()
+	assert.Equal(t, map[string]string{"quantity": "out_of_range"}, codes(err))
+}
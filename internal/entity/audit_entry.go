@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+)
+
+// AuditChange is the value of one JSON field before and after a mutation.
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditChanges maps top-level JSON field names to how they changed. It is
+// stored as a JSON document.
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	*c = nil
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return errors.New("unsupported audit changes type")
+	}
+}
+
+// DiffJSON compares the JSON encodings of before and after field by field.
+// Either side may be nil, as for creations and deletions.
+func DiffJSON(before, after interface{}) (AuditChanges, error) {
+	from, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := AuditChanges{}
+	for field, value := range from {
+		if !reflect.DeepEqual(value, to[field]) {
+			changes[field] = AuditChange{From: value, To: to[field]}
+		}
+	}
+	for field, value := range to {
+		if _, ok := from[field]; !ok {
+			changes[field] = AuditChange{To: value}
+		}
+	}
+	return changes, nil
+}
+
+func jsonFields(v interface{}) (map[string]interface{}, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return map[string]interface{}{}, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
+
+// AuditEntry records one write made through the API. Entries are never
+// updated or deleted.
+type AuditEntry struct {
+	ID         entity.ID    `json:"id"`
+	ActorID    *entity.ID   `json:"actor_id"`
+	Action     string       `json:"action"`
+	EntityType string       `json:"entity_type"`
+	EntityID   string       `json:"entity_id"`
+	Changes    AuditChanges `json:"changes" swaggertype:"object"`
+	RequestID  string       `json:"request_id"`
+	ClientIP   string       `json:"client_ip"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
+
+func NewAuditEntry(actorID *entity.ID, action, entityType, entityID string, changes AuditChanges) *AuditEntry {
+	return &AuditEntry{
+		ID:         entity.NewID(),
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		CreatedAt:  time.Now(),
+	}
+}
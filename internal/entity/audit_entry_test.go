@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffJSON(t *testing.T) {
+	before, _ := NewProduct("Product 1", brl(1000))
+	after := *before
+	after.Name = "Product 2"
+	after.Version = 2
+
+	changes, err := DiffJSON(before, &after)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, AuditChange{From: "Product 1", To: "Product 2"}, changes["name"])
+	assert.Equal(t, AuditChange{From: float64(1), To: float64(2)}, changes["version"])
+
+	var missing *Product
+	changes, err = DiffJSON(missing, before)
+	assert.Nil(t, err)
+	assert.Equal(t, AuditChange{To: "Product 1"}, changes["name"])
+	assert.Contains(t, changes, "price")
+
+	changes, err = DiffJSON(before, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, AuditChange{From: "Product 1"}, changes["name"])
+}
+
+func TestAuditChanges_Scan(t *testing.T) {
+	changes := AuditChanges{"name": {From: "a", To: "b"}}
+	value, err := changes.Value()
+	assert.Nil(t, err)
+
+	var scanned AuditChanges
+	assert.Nil(t, scanned.Scan(value))
+	assert.Equal(t, changes, scanned)
+	assert.Nil(t, scanned.Scan([]byte(`{}`)))
+	assert.Empty(t, scanned)
+}
@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+var (
+	ErrCartFull           = errors.New("cart cannot have more than 50 products")
+	ErrProductUnavailable = errors.New("product is not available")
+)
+
+const (
+	MaxCartItems        = 50
+	MaxCartItemQuantity = 999
+)
+
+// Cart item statuses. An item whose product changed price keeps
+// CartItemPriceChanged until the user changes its quantity, so clients can
+// point the change out. Unavailable items belong to deleted products and do
+// not count towards the totals.
+const (
+	CartItemAvailable    = "available"
+	CartItemPriceChanged = "price_changed"
+	CartItemUnavailable  = "unavailable"
+)
+
+// CartItem is a product in a user's cart. Name and UnitPrice are snapshots
+// of the product taken when the cart was last revalidated; PreviousPrice is
+// the snapshot it replaced when the status is CartItemPriceChanged.
+type CartItem struct {
+	ProductID     entity.ID     `json:"product_id"`
+	Name          string        `json:"name"`
+	Quantity      int64         `json:"quantity"`
+	UnitPrice     entity.Money  `json:"unit_price"`
+	PreviousPrice *entity.Money `json:"previous_price,omitempty"`
+	Subtotal      entity.Money  `json:"subtotal"`
+	Status        string        `json:"status" enums:"available,price_changed,unavailable"`
+	AddedAt       time.Time     `json:"added_at"`
+}
+
+func NewCartItem(product *Product, quantity int64) (*CartItem, error) {
+	if quantity <= 0 || quantity > MaxCartItemQuantity {
+		return nil, ErrInvalidQuantity
+	}
+	return &CartItem{
+		ProductID: product.ID,
+		Name:      product.Name,
+		Quantity:  quantity,
+		UnitPrice: product.Price,
+		Status:    CartItemAvailable,
+		AddedAt:   time.Now(),
+	}, nil
+}
+
+// SetQuantity changes the quantity, which also acknowledges a price change.
+func (i *CartItem) SetQuantity(quantity int64) error {
+	if quantity <= 0 || quantity > MaxCartItemQuantity {
+		return ErrInvalidQuantity
+	}
+	i.Quantity = quantity
+	if i.Status == CartItemPriceChanged {
+		i.Status = CartItemAvailable
+		i.PreviousPrice = nil
+	}
+	return nil
+}
+
+// Revalidate brings the snapshots up to date with product, which is nil
+// when the product was deleted, and reports whether anything changed. A
+// price that changes back before the user noticed is no longer reported.
+func (i *CartItem) Revalidate(product *Product) bool {
+	if product == nil {
+		changed := i.Status != CartItemUnavailable
+		i.Status = CartItemUnavailable
+		i.PreviousPrice = nil
+		return changed
+	}
+	changed := false
+	if i.Status == CartItemUnavailable {
+		i.Status = CartItemAvailable
+		changed = true
+	}
+	if i.Name != product.Name {
+		i.Name = product.Name
+		changed = true
+	}
+	if i.UnitPrice != product.Price {
+		if i.PreviousPrice == nil {
+			previous := i.UnitPrice
+			i.PreviousPrice = &previous
+		}
+		i.UnitPrice = product.Price
+		i.Status = CartItemPriceChanged
+		if *i.PreviousPrice == i.UnitPrice {
+			i.Status = CartItemAvailable
+			i.PreviousPrice = nil
+		}
+		changed = true
+	}
+	return changed
+}
+
+// Cart is the cart of a user, made of its items. Totals has one amount per
+// currency of the available items, ordered by currency.
+type Cart struct {
+	UserID    entity.ID      `json:"user_id"`
+	Items     []CartItem     `json:"items"`
+	ItemCount int64          `json:"item_count"`
+	Totals    []entity.Money `json:"totals"`
+}
+
+func NewCart(userID entity.ID, items []CartItem) *Cart {
+	cart := &Cart{UserID: userID, Items: []CartItem{}, Totals: []entity.Money{}}
+	totals := map[string]int64{}
+	for _, item := range items {
+		item.Subtotal = entity.Money{Amount: item.UnitPrice.Amount * item.Quantity, Currency: item.UnitPrice.Currency}
+		cart.Items = append(cart.Items, item)
+		if item.Status == CartItemUnavailable {
+			continue
+		}
+		cart.ItemCount += item.Quantity
+		totals[item.Subtotal.Currency] += item.Subtotal.Amount
+	}
+	for currency, amount := range totals {
+		cart.Totals = append(cart.Totals, entity.Money{Amount: amount, Currency: currency})
+	}
+	sort.Slice(cart.Totals, func(a, b int) bool {
+		return cart.Totals[a].Currency < cart.Totals[b].Currency
+	})
+	return cart
+}
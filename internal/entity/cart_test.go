@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCartItem(t *testing.T) {
+	product, _ := NewProduct("Shirt", entity.Money{Amount: 1000, Currency: "BRL"})
+	item, err := NewCartItem(product, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, product.ID, item.ProductID)
+	assert.Equal(t, "Shirt", item.Name)
+	assert.Equal(t, product.Price, item.UnitPrice)
+	assert.Equal(t, CartItemAvailable, item.Status)
+
+	_, err = NewCartItem(product, 0)
+	assert.Equal(t, ErrInvalidQuantity, err)
+	_, err = NewCartItem(product, MaxCartItemQuantity+1)
+	assert.Equal(t, ErrInvalidQuantity, err)
+}
+
+func TestCartItem_Revalidate(t *testing.T) {
+	product, _ := NewProduct("Shirt", entity.Money{Amount: 1000, Currency: "BRL"})
+	item, _ := NewCartItem(product, 1)
+	assert.False(t, item.Revalidate(product))
+
+	product.Price.Amount = 1200
+	assert.True(t, item.Revalidate(product))
+	assert.Equal(t, CartItemPriceChanged, item.Status)
+	assert.Equal(t, int64(1200), item.UnitPrice.Amount)
+	assert.Equal(t, int64(1000), item.PreviousPrice.Amount)
+
+	product.Price.Amount = 1500
+	assert.True(t, item.Revalidate(product))
+	assert.Equal(t, int64(1000), item.PreviousPrice.Amount)
+
+	product.Price.Amount = 1000
+	assert.True(t, item.Revalidate(product))
+	assert.Equal(t, CartItemAvailable, item.Status)
+	assert.Nil(t, item.PreviousPrice)
+
+	assert.True(t, item.Revalidate(nil))
+	assert.Equal(t, CartItemUnavailable, item.Status)
+	assert.False(t, item.Revalidate(nil))
+
+	product.Name = "T-Shirt"
+	assert.True(t, item.Revalidate(product))
+	assert.Equal(t, CartItemAvailable, item.Status)
+	assert.Equal(t, "T-Shirt", item.Name)
+}
+
+func TestCartItem_SetQuantity(t *testing.T) {
+	product, _ := NewProduct("Shirt", entity.Money{Amount: 1000, Currency: "BRL"})
+	item, _ := NewCartItem(product, 1)
+	product.Price.Amount = 1200
+	item.Revalidate(product)
+
+	assert.Equal(t, ErrInvalidQuantity, item.SetQuantity(0))
+	assert.Nil(t, item.SetQuantity(3))
+	assert.Equal(t, int64(3), item.Quantity)
+	assert.Equal(t, CartItemAvailable, item.Status)
+	assert.Nil(t, item.PreviousPrice)
+}
+
+func TestNewCart(t *testing.T) {
+	shirt, _ := NewProduct("Shirt", entity.Money{Amount: 1000, Currency: "BRL"})
+	mug, _ := NewProduct("Mug", entity.Money{Amount: 550, Currency: "BRL"})
+	book, _ := NewProduct("Book", entity.Money{Amount: 2000, Currency: "USD"})
+	gone, _ := NewProduct("Gone", entity.Money{Amount: 9999, Currency: "BRL"})
+	var items []CartItem
+	for _, p := range []struct {
+		product  *Product
+		quantity int64
+	}{{book, 1}, {shirt, 2}, {mug, 3}, {gone, 1}} {
+		item, _ := NewCartItem(p.product, p.quantity)
+		items = append(items, *item)
+	}
+	items[3].Revalidate(nil)
+
+	cart := NewCart(entity.NewID(), items)
+	assert.Len(t, cart.Items, 4)
+	assert.Equal(t, entity.Money{Amount: 1650, Currency: "BRL"}, cart.Items[2].Subtotal)
+	assert.Equal(t, int64(6), cart.ItemCount)
+	assert.Equal(t, []entity.Money{{Amount: 3650, Currency: "BRL"}, {Amount: 2000, Currency: "USD"}}, cart.Totals)
+
+	empty := NewCart(entity.NewID(), nil)
+	assert.Equal(t, []CartItem{}, empty.Items)
+	assert.Equal(t, []entity.Money{}, empty.Totals)
+}
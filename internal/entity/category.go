@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+var (
+	ErrCategoryNotFound    = errors.New("category not found")
+	ErrCategoryCycle       = errors.New("category cannot be placed under itself or one of its subcategories")
+	ErrCategoryHasChildren = errors.New("category still has subcategories")
+)
+
+// Category is a node of the catalog tree; root categories have no parent.
+type Category struct {
+	ID        entity.ID  `json:"id"`
+	Name      string     `json:"name"`
+	ParentID  *entity.ID `json:"parent_id"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func NewCategory(name string, parentID *entity.ID) (*Category, error) {
+	category := &Category{
+		ID:        entity.NewID(),
+		Name:      name,
+		ParentID:  parentID,
+		CreatedAt: time.Now(),
+	}
+	err := category.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// Validate only catches a category being its own parent; deeper cycles need
+// the rest of the tree and are checked when saving.
+func (c *Category) Validate() error {
+	if c.ID.String() == "" {
+		return ErrIDIsRequired
+	}
+	if _, err := entity.ParseID(c.ID.String()); err != nil {
+		return ErrInvalidID
+	}
+	if c.Name == "" {
+		return ErrNameIsRequired
+	}
+	if c.ParentID != nil && *c.ParentID == c.ID {
+		return ErrCategoryCycle
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCategory(t *testing.T) {
+	parentID := entity.NewID()
+	c, err := NewCategory("Books", &parentID)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, c.ID)
+	assert.Equal(t, "Books", c.Name)
+	assert.Equal(t, &parentID, c.ParentID)
+
+	c, err = NewCategory("", nil)
+	assert.Nil(t, c)
+	assert.Equal(t, ErrNameIsRequired, err)
+}
+
+func TestCategory_ValidateOwnParent(t *testing.T) {
+	c, err := NewCategory("Books", nil)
+	assert.Nil(t, err)
+	c.ParentID = &c.ID
+	assert.Equal(t, ErrCategoryCycle, c.Validate())
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+var (
+	ErrInvalidExchangeRate     = errors.New("exchange rate must be a positive decimal number between two different currencies")
+	ErrExchangeRateUnavailable = errors.New("no exchange rate is available for the requested currency")
+)
+
+var decimalPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// ExchangeRate says how many units of Quote one unit of Base buys. Rate is
+// kept as a decimal string so it round-trips without float errors.
+type ExchangeRate struct {
+	Base      string    `json:"base" gorm:"primaryKey"`
+	Quote     string    `json:"quote" gorm:"primaryKey"`
+	Rate      string    `json:"rate" example:"0.1834"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewExchangeRate(base, quote, rate string) (*ExchangeRate, error) {
+	exchangeRate := &ExchangeRate{
+		Base:      base,
+		Quote:     quote,
+		Rate:      rate,
+		UpdatedAt: time.Now(),
+	}
+	if err := exchangeRate.Validate(); err != nil {
+		return nil, err
+	}
+	return exchangeRate, nil
+}
+
+func (e *ExchangeRate) Validate() error {
+	if !entity.IsValidCurrency(e.Base) || !entity.IsValidCurrency(e.Quote) {
+		return entity.ErrInvalidCurrency
+	}
+	if e.Base == e.Quote || !decimalPattern.MatchString(e.Rate) {
+		return ErrInvalidExchangeRate
+	}
+	if e.rat().Sign() <= 0 {
+		return ErrInvalidExchangeRate
+	}
+	return nil
+}
+
+func (e *ExchangeRate) rat() *big.Rat {
+	rate, ok := new(big.Rat).SetString(e.Rate)
+	if !ok {
+		return new(big.Rat)
+	}
+	return rate
+}
+
+// Inverse returns the Quote to Base rate, rounded to 10 decimal digits.
+func (e *ExchangeRate) Inverse() *ExchangeRate {
+	inverse := new(big.Rat).Inv(e.rat()).FloatString(10)
+	inverse = strings.TrimRight(strings.TrimRight(inverse, "0"), ".")
+	return &ExchangeRate{
+		Base:      e.Quote,
+		Quote:     e.Base,
+		Rate:      inverse,
+		UpdatedAt: e.UpdatedAt,
+	}
+}
+
+// Convert turns an amount in Base into Quote.
+func (e *ExchangeRate) Convert(money entity.Money) (entity.Money, error) {
+	if money.Currency != e.Base {
+		return entity.Money{}, ErrExchangeRateUnavailable
+	}
+	return money.Convert(e.Quote, e.rat())
+}
@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExchangeRate(t *testing.T) {
+	rate, err := NewExchangeRate("BRL", "USD", "0.2")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, rate.UpdatedAt)
+
+	_, err = NewExchangeRate("BRL", "XYZ", "0.2")
+	assert.Equal(t, entity.ErrInvalidCurrency, err)
+
+	for _, invalid := range []string{"", "0", "-1", "1e3", "1/3", "abc"} {
+		_, err = NewExchangeRate("BRL", "USD", invalid)
+		assert.Equal(t, ErrInvalidExchangeRate, err, invalid)
+	}
+	_, err = NewExchangeRate("BRL", "BRL", "1")
+	assert.Equal(t, ErrInvalidExchangeRate, err)
+}
+
+func TestExchangeRate_Convert(t *testing.T) {
+	rate, _ := NewExchangeRate("BRL", "USD", "0.2")
+
+	money, err := rate.Convert(brl(1250))
+	assert.Nil(t, err)
+	assert.Equal(t, entity.Money{Amount: 250, Currency: "USD"}, money)
+
+	_, err = rate.Convert(entity.Money{Amount: 100, Currency: "EUR"})
+	assert.Equal(t, ErrExchangeRateUnavailable, err)
+
+	inverse := rate.Inverse()
+	assert.Equal(t, "USD", inverse.Base)
+	assert.Equal(t, "BRL", inverse.Quote)
+	assert.Equal(t, "5", inverse.Rate)
+	assert.Equal(t, rate.UpdatedAt, inverse.UpdatedAt)
+}
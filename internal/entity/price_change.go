@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+// PriceChange records a product price being replaced. ChangedBy is nil when
+// the change was not made on behalf of a user.
+type PriceChange struct {
+	ID        entity.ID    `json:"id"`
+	ProductID entity.ID    `json:"product_id"`
+	OldPrice  entity.Money `json:"old_price" gorm:"embedded;embeddedPrefix:old_price_"`
+	NewPrice  entity.Money `json:"new_price" gorm:"embedded;embeddedPrefix:new_price_"`
+	ChangedBy *entity.ID   `json:"changed_by"`
+	ChangedAt time.Time    `json:"changed_at"`
+}
+
+func (PriceChange) TableName() string {
+	return "product_price_history"
+}
+
+func NewPriceChange(productID entity.ID, oldPrice, newPrice entity.Money, changedBy *entity.ID) *PriceChange {
+	return &PriceChange{
+		ID:        entity.NewID(),
+		ProductID: productID,
+		OldPrice:  oldPrice,
+		NewPrice:  newPrice,
+		ChangedBy: changedBy,
+		ChangedAt: time.Now(),
+	}
+}
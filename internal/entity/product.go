@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"gorm.io/gorm"
 )
 
 var (
@@ -14,20 +15,30 @@ var (
 	ErrNameIsRequired  = errors.New("name is required")
 	ErrPriceIsRequired = errors.New("price is required")
 	ErrInvalidPrice    = errors.New("invalid price")
+	ErrVersionConflict = errors.New("product was modified by another request")
 )
 
+// Product is a catalog item. Stock is the quantity available for sale; it is
+// not covered by Version since it changes independently of the catalog data.
 type Product struct {
-	ID        entity.ID `json:"id"`
-	Name      string    `json:"name"`
-	Price     int       `json:"price"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        entity.ID      `json:"id"`
+	Name      string         `json:"name"`
+	Price     entity.Money   `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	OwnerID   *entity.ID     `json:"owner_id"`
+	Tags      []string       `json:"tags" gorm:"-"`
+	Images    []ProductImage `json:"images" gorm:"-"`
+	Stock     int64          `json:"stock"`
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" swaggertype:"string" format:"date-time"`
 }
 
-func NewProduct(name string, price int) (*Product, error) {
+func NewProduct(name string, price entity.Money) (*Product, error) {
 	product := &Product{
 		ID:        entity.NewID(),
 		Name:      name,
 		Price:     price,
+		Version:   1,
 		CreatedAt: time.Now(),
 	}
 	err := product.Validate()
@@ -37,6 +48,16 @@ func NewProduct(name string, price int) (*Product, error) {
 	return product, nil
 }
 
+// IsOwnedBy reports whether userID created the product. Products created
+// before ownership was tracked have no owner.
+func (p *Product) IsOwnedBy(userID string) bool {
+	return p.OwnerID != nil && p.OwnerID.String() == userID
+}
+
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt.Valid
+}
+
 func (p *Product) Validate() error {
 	fmt.Println("Price:", p.Price)
 	if p.ID.String() == "" {
@@ -48,11 +69,19 @@ func (p *Product) Validate() error {
 	if p.Name == "" {
 		return ErrNameIsRequired
 	}
-	if p.Price == 0 {
+	if p.Price.IsZero() {
 		return ErrPriceIsRequired
 	}
-	if p.Price < 0 {
+	if p.Price.IsNegative() {
 		return ErrInvalidPrice
 	}
+	if !entity.IsValidCurrency(p.Price.Currency) {
+		return entity.ErrInvalidCurrency
+	}
+	for _, tag := range p.Tags {
+		if normalized, err := NormalizeTag(tag); err != nil || normalized != tag {
+			return ErrInvalidTag
+		}
+	}
 	return nil
 }
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+var (
+	ErrUnsupportedImageType = errors.New("image must be a JPEG, PNG or GIF")
+	ErrImageTooLarge        = errors.New("image is too large")
+	ErrInvalidImage         = errors.New("image could not be decoded")
+	ErrTooManyImages        = errors.New("product already has the maximum number of images")
+)
+
+const (
+	MaxProductImages = 10
+	// MaxImagePixels guards against small files that decode to huge images.
+	MaxImagePixels = 40_000_000
+)
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+func IsSupportedImageType(contentType string) bool {
+	_, ok := imageExtensions[contentType]
+	return ok
+}
+
+// ProductImage is an image of a product kept in file storage along with a
+// thumbnail. URL and ThumbnailURL are resolved when the image is uploaded.
+type ProductImage struct {
+	ID           entity.ID `json:"id"`
+	ProductID    entity.ID `json:"product_id"`
+	ContentType  string    `json:"content_type" example:"image/jpeg"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Key          string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewProductImage names the storage keys of the image and its thumbnail
+// after the product and the image ID.
+func NewProductImage(productID entity.ID, contentType, thumbnailType string, size int64, width, height int) (*ProductImage, error) {
+	if !IsSupportedImageType(contentType) || !IsSupportedImageType(thumbnailType) {
+		return nil, ErrUnsupportedImageType
+	}
+	if width <= 0 || height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if width*height > MaxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+	id := entity.NewID()
+	prefix := "products/" + productID.String() + "/" + id.String()
+	return &ProductImage{
+		ID:           id,
+		ProductID:    productID,
+		ContentType:  contentType,
+		Size:         size,
+		Width:        width,
+		Height:       height,
+		Key:          prefix + imageExtensions[contentType],
+		ThumbnailKey: prefix + "_thumb" + imageExtensions[thumbnailType],
+		CreatedAt:    time.Now(),
+	}, nil
+}
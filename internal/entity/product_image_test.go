@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProductImage(t *testing.T) {
+	productID := entity.NewID()
+	image, err := NewProductImage(productID, "image/gif", "image/png", 1024, 640, 480)
+	assert.Nil(t, err)
+	prefix := "products/" + productID.String() + "/" + image.ID.String()
+	assert.Equal(t, prefix+".gif", image.Key)
+	assert.Equal(t, prefix+"_thumb.png", image.ThumbnailKey)
+	assert.Equal(t, int64(1024), image.Size)
+
+	_, err = NewProductImage(productID, "image/webp", "image/png", 1024, 640, 480)
+	assert.Equal(t, ErrUnsupportedImageType, err)
+	_, err = NewProductImage(productID, "image/png", "image/png", 1024, 0, 480)
+	assert.Equal(t, ErrInvalidImage, err)
+	_, err = NewProductImage(productID, "image/png", "image/png", 1024, 10000, 5000)
+	assert.Equal(t, ErrImageTooLarge, err)
+}
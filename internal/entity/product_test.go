@@ -3,43 +3,76 @@ package entity
 import (
 	"testing"
 
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+
 	"github.com/stretchr/testify/assert"
 )
 
+func brl(amount int64) entity.Money {
+	return entity.Money{Amount: amount, Currency: "BRL"}
+}
+
 func TestProduct(t *testing.T) {
-	p, err := NewProduct("Product 1", 10)
+	p, err := NewProduct("Product 1", brl(1000))
 	assert.Nil(t, err)
 	assert.NotNil(t, p)
 	assert.NotEmpty(t, p.ID)
 	assert.Equal(t, "Product 1", p.Name)
-	assert.Equal(t, 10, p.Price)
+	assert.Equal(t, brl(1000), p.Price)
+	assert.Equal(t, 1, p.Version)
 }
 
 func TestProduct_WhenNameIsRequired(t *testing.T) {
-	p, err := NewProduct("", 10)
+	p, err := NewProduct("", brl(1000))
 	assert.NotNil(t, err)
 	assert.Nil(t, p)
 	assert.Equal(t, ErrNameIsRequired, err)
 }
 
 func TestProduct_WhenPriceIsRequired(t *testing.T) {
-	p, err := NewProduct("Product 1", 0)
+	p, err := NewProduct("Product 1", brl(0))
 	assert.NotNil(t, err)
 	assert.Nil(t, p)
 	assert.Equal(t, ErrPriceIsRequired, err)
 }
 
 func TestProduct_WhenPriceIsInvalid(t *testing.T) {
-	p, err := NewProduct("Product 1", -1)
+	p, err := NewProduct("Product 1", brl(-100))
 	assert.NotNil(t, err)
 	assert.Nil(t, p)
 	assert.Equal(t, ErrInvalidPrice, err)
 }
 
+func TestProduct_WhenCurrencyIsInvalid(t *testing.T) {
+	p, err := NewProduct("Product 1", entity.Money{Amount: 1000, Currency: "XYZ"})
+	assert.Nil(t, p)
+	assert.Equal(t, entity.ErrInvalidCurrency, err)
+}
+
 func TestProduct_Validate(t *testing.T) {
-	p, err := NewProduct("Product 1", 10)
+	p, err := NewProduct("Product 1", brl(1000))
 	assert.Nil(t, err)
 	assert.NotNil(t, p)
 	err = p.Validate()
 	assert.Nil(t, err)
 }
+
+func TestProduct_IsOwnedBy(t *testing.T) {
+	p, err := NewProduct("Product 1", brl(1000))
+	assert.Nil(t, err)
+	ownerID := entity.NewID()
+	assert.False(t, p.IsOwnedBy(ownerID.String()))
+
+	p.OwnerID = &ownerID
+	assert.True(t, p.IsOwnedBy(ownerID.String()))
+	assert.False(t, p.IsOwnedBy(entity.NewID().String()))
+}
+
+func TestProduct_ValidateTags(t *testing.T) {
+	p, err := NewProduct("Product 1", brl(1000))
+	assert.Nil(t, err)
+	p.Tags = []string{"sale"}
+	assert.Nil(t, p.Validate())
+	p.Tags = []string{"Sale"}
+	assert.Equal(t, ErrInvalidTag, p.Validate())
+}
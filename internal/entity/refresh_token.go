@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+)
+
+// RefreshToken is the server-side record of an opaque refresh token. Only the
+// hash of the token is stored; every token issued by rotating another one
+// shares the FamilyID of the token issued at login.
+type RefreshToken struct {
+	ID        entity.ID  `json:"id"`
+	UserID    entity.ID  `json:"user_id"`
+	FamilyID  entity.ID  `json:"family_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+// NewRefreshToken returns the record to persist and the plain token to hand
+// to the client. A zero familyID starts a new family.
+func NewRefreshToken(userID, familyID entity.ID, expiration int) (*RefreshToken, string, error) {
+	token, err := entity.GenerateOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	if familyID == (entity.ID{}) {
+		familyID = entity.NewID()
+	}
+	now := time.Now()
+	return &RefreshToken{
+		ID:        entity.NewID(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: entity.HashToken(token),
+		ExpiresAt: now.Add(time.Second * time.Duration(expiration)),
+		CreatedAt: now,
+	}, token, nil
+}
+
+// Validate reports why the token can no longer be exchanged, if at all.
+// A token that was already rotated or revoked is reported as reused.
+func (t *RefreshToken) Validate() error {
+	if t.RotatedAt != nil || t.RevokedAt != nil {
+		return ErrRefreshTokenReused
+	}
+	if time.Now().After(t.ExpiresAt) {
+		return ErrRefreshTokenExpired
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshToken(t *testing.T) {
+	userID := pkgEntity.NewID()
+	rt, token, err := NewRefreshToken(userID, pkgEntity.ID{}, 60)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, token)
+	assert.Equal(t, userID, rt.UserID)
+	assert.NotEqual(t, pkgEntity.ID{}, rt.FamilyID)
+	assert.Equal(t, pkgEntity.HashToken(token), rt.TokenHash)
+	assert.NotEqual(t, token, rt.TokenHash)
+	assert.Nil(t, rt.Validate())
+
+	next, nextToken, err := NewRefreshToken(userID, rt.FamilyID, 60)
+	assert.Nil(t, err)
+	assert.Equal(t, rt.FamilyID, next.FamilyID)
+	assert.NotEqual(t, token, nextToken)
+}
+
+func TestRefreshToken_Validate(t *testing.T) {
+	rt, _, err := NewRefreshToken(pkgEntity.NewID(), pkgEntity.ID{}, 60)
+	assert.Nil(t, err)
+
+	now := time.Now()
+	rt.RotatedAt = &now
+	assert.Equal(t, ErrRefreshTokenReused, rt.Validate())
+
+	rt.RotatedAt = nil
+	rt.ExpiresAt = now.Add(-time.Second)
+	assert.Equal(t, ErrRefreshTokenExpired, rt.Validate())
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// RevokedToken is a denylist entry for an access token identified by its jti
+// claim. It only needs to be kept until the token would have expired anyway.
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+func NewRevokedToken(jti string, expiresAt time.Time) *RevokedToken {
+	return &RevokedToken{
+		JTI:       jti,
+		ExpiresAt: expiresAt,
+		RevokedAt: time.Now(),
+	}
+}
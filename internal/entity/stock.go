@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+var (
+	ErrInvalidQuantity       = errors.New("quantity must be a positive whole number")
+	ErrInvalidStockReason    = errors.New("invalid stock adjustment reason")
+	ErrInsufficientStock     = errors.New("not enough stock")
+	ErrReservationNotHeld    = errors.New("reservation was already committed or released")
+	ErrReservationExpired    = errors.New("reservation has expired")
+	ErrInvalidReservationTTL = errors.New("reservation ttl is out of range")
+)
+
+// Reasons a stock level may be adjusted by hand.
+const (
+	StockReasonReceived   = "received"
+	StockReasonReturned   = "returned"
+	StockReasonDamaged    = "damaged"
+	StockReasonLost       = "lost"
+	StockReasonSold       = "sold"
+	StockReasonCorrection = "correction"
+)
+
+var stockReasons = map[string]bool{
+	StockReasonReceived:   true,
+	StockReasonReturned:   true,
+	StockReasonDamaged:    true,
+	StockReasonLost:       true,
+	StockReasonSold:       true,
+	StockReasonCorrection: true,
+}
+
+func IsValidStockReason(reason string) bool {
+	return stockReasons[reason]
+}
+
+// StockAdjustment records a change to a product's stock level. Delta is
+// positive when stock is added and negative when it is removed. When made at
+// a warehouse, the warehouse's quantity changes too.
+type StockAdjustment struct {
+	ID          entity.ID  `json:"id"`
+	ProductID   entity.ID  `json:"product_id"`
+	Delta       int64      `json:"delta"`
+	Reason      string     `json:"reason"`
+	Note        string     `json:"note,omitempty"`
+	WarehouseID *entity.ID `json:"warehouse_id,omitempty"`
+	ActorID     *entity.ID `json:"actor_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func NewStockAdjustment(productID entity.ID, warehouseID *entity.ID, delta int64, reason, note string, actorID *entity.ID) (*StockAdjustment, error) {
+	adjustment := &StockAdjustment{
+		ID:          entity.NewID(),
+		ProductID:   productID,
+		Delta:       delta,
+		Reason:      reason,
+		Note:        note,
+		WarehouseID: warehouseID,
+		ActorID:     actorID,
+		CreatedAt:   time.Now(),
+	}
+	err := adjustment.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return adjustment, nil
+}
+
+func (a *StockAdjustment) Validate() error {
+	if a.Delta == 0 {
+		return ErrInvalidQuantity
+	}
+	if !IsValidStockReason(a.Reason) {
+		return ErrInvalidStockReason
+	}
+	return nil
+}
+
+// Reservation states. A held reservation becomes committed when the goods
+// leave, or released when they return to stock, by request or on expiry.
+const (
+	ReservationHeld      = "held"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+)
+
+const (
+	DefaultReservationTTL = 15 * time.Minute
+	MaxReservationTTL     = 24 * time.Hour
+)
+
+// Reservation takes Quantity out of a product's available stock until it
+// is committed or released. It is released automatically after ExpiresAt.
+type Reservation struct {
+	ID        entity.ID  `json:"id"`
+	ProductID entity.ID  `json:"product_id"`
+	Quantity  int64      `json:"quantity"`
+	Status    string     `json:"status"`
+	CreatedBy *entity.ID `json:"created_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (Reservation) TableName() string {
+	return "stock_reservations"
+}
+
+func NewReservation(productID entity.ID, quantity int64, ttl time.Duration, createdBy *entity.ID) (*Reservation, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if ttl <= 0 || ttl > MaxReservationTTL {
+		return nil, ErrInvalidReservationTTL
+	}
+	now := time.Now()
+	return &Reservation{
+		ID:        entity.NewID(),
+		ProductID: productID,
+		Quantity:  quantity,
+		Status:    ReservationHeld,
+		CreatedBy: createdBy,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, nil
+}
+
+// IsExpired reports whether a held reservation has outlived its TTL.
+func (r *Reservation) IsExpired(now time.Time) bool {
+	return r.Status == ReservationHeld && !now.Before(r.ExpiresAt)
+}
@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStockAdjustment(t *testing.T) {
+	productID := entity.NewID()
+	a, err := NewStockAdjustment(productID, nil, -3, StockReasonDamaged, "dropped", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-3), a.Delta)
+	assert.Equal(t, productID, a.ProductID)
+
+	_, err = NewStockAdjustment(productID, nil, 0, StockReasonReceived, "", nil)
+	assert.Equal(t, ErrInvalidQuantity, err)
+	_, err = NewStockAdjustment(productID, nil, 1, "gift", "", nil)
+	assert.Equal(t, ErrInvalidStockReason, err)
+}
+
+func TestNewReservation(t *testing.T) {
+	r, err := NewReservation(entity.NewID(), 2, time.Minute, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ReservationHeld, r.Status)
+	assert.False(t, r.IsExpired(time.Now()))
+	assert.True(t, r.IsExpired(time.Now().Add(time.Minute)))
+
+	_, err = NewReservation(entity.NewID(), 0, time.Minute, nil)
+	assert.Equal(t, ErrInvalidQuantity, err)
+	_, err = NewReservation(entity.NewID(), 1, 25*time.Hour, nil)
+	assert.Equal(t, ErrInvalidReservationTTL, err)
+}
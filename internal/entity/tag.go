@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidTag = errors.New("tags must have 1 to 50 characters and no commas")
+
+const tagMaxLength = 50
+
+// TagUsage is a tag and how many products carry it.
+type TagUsage struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// NormalizeTag trims and lowercases a tag so "Sale" and " sale" are the same
+// tag. Commas are rejected because tag filters are comma separated.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || utf8.RuneCountInString(tag) > tagMaxLength || strings.Contains(tag, ",") {
+		return "", ErrInvalidTag
+	}
+	return tag, nil
+}
+
+// NormalizeTags normalizes every tag and drops duplicates, keeping the
+// first occurrence's position.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Sale", "summer", "sale"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sale", "summer"}, tags)
+
+	for _, tag := range []string{"", "  ", "a,b", strings.Repeat("a", 51)} {
+		_, err = NormalizeTag(tag)
+		assert.Equal(t, ErrInvalidTag, err, tag)
+	}
+}
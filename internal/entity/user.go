@@ -1,15 +1,30 @@
 package entity
 
 import (
+	"errors"
+
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+var (
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailAlreadyExists = errors.New("email already registered")
+)
+
 type User struct {
 	ID       entity.ID `json:"id"`
 	Name     string    `json:"name"`
 	Email    string    `json:"email"`
 	Password string    `json:"-"`
+	Role     string    `json:"role"`
 }
 
 func NewUser(name, email, password string) (*User, error) {
@@ -22,6 +37,7 @@ func NewUser(name, email, password string) (*User, error) {
 		Name:     name,
 		Email:    email,
 		Password: string(hash),
+		Role:     RoleViewer,
 	}, nil
 }
 
@@ -29,3 +45,15 @@ func (u *User) ComparePassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
 	return err == nil
 }
+
+func (u *User) SetRole(role string) error {
+	if !IsValidRole(role) {
+		return ErrInvalidRole
+	}
+	u.Role = role
+	return nil
+}
+
+func IsValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleEditor || role == RoleViewer
+}
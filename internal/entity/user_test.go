@@ -14,6 +14,7 @@ func TestUser(t *testing.T) {
 	assert.NotEmpty(t, user.Password)
 	assert.Equal(t, "Felipe Dias", user.Name)
 	assert.Equal(t, "felipe@gmail.com", user.Email)
+	assert.Equal(t, RoleViewer, user.Role)
 }
 
 func TestUser_ComparePassword(t *testing.T) {
@@ -23,3 +24,12 @@ func TestUser_ComparePassword(t *testing.T) {
 	assert.False(t, user.ComparePassword("1234567"))
 	assert.NotEqual(t, "123456", user.Password)
 }
+
+func TestUser_SetRole(t *testing.T) {
+	user, err := NewUser("Felipe Dias", "felipe@gmail.com", "123456")
+	assert.Nil(t, err)
+	assert.Nil(t, user.SetRole(RoleEditor))
+	assert.Equal(t, RoleEditor, user.Role)
+	assert.Equal(t, ErrInvalidRole, user.SetRole("root"))
+	assert.Equal(t, RoleEditor, user.Role)
+}
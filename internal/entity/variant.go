@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+var (
+	ErrInvalidSKU            = errors.New("sku must have 1 to 64 letters, digits, dots, dashes or underscores")
+	ErrSKUExists             = errors.New("sku already exists")
+	ErrInvalidVariantOptions = errors.New("variant options must have 1 to 10 attributes with lowercase names and non-empty values")
+	ErrVariantOptionsExist   = errors.New("another variant of the product has the same options")
+)
+
+const (
+	VariantMaxOptions     = 10
+	variantOptionMaxValue = 50
+)
+
+var (
+	skuPattern           = regexp.MustCompile(`^[A-Z0-9][A-Z0-9._-]{0,63}$`)
+	variantOptionPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+)
+
+// Variant is a sellable version of a product, such as a shirt in one size
+// and color. Price overrides the product's price when set.
+type Variant struct {
+	ID        entity.ID         `json:"id"`
+	ProductID entity.ID         `json:"product_id"`
+	SKU       string            `json:"sku" example:"SHIRT-M-RED"`
+	Options   map[string]string `json:"options"`
+	Price     *entity.Money     `json:"price"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// NewVariant upper-cases the SKU and lower-cases the option names, trimming
+// both names and values.
+func NewVariant(productID entity.ID, sku string, options map[string]string, price *entity.Money) (*Variant, error) {
+	variant := &Variant{
+		ID:        entity.NewID(),
+		ProductID: productID,
+		SKU:       NormalizeSKU(sku),
+		Options:   NormalizeVariantOptions(options),
+		Price:     price,
+		CreatedAt: time.Now(),
+	}
+	err := variant.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+func NormalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
+
+func NormalizeVariantOptions(options map[string]string) map[string]string {
+	normalized := make(map[string]string, len(options))
+	for name, value := range options {
+		normalized[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	return normalized
+}
+
+func IsValidSKU(sku string) bool {
+	return skuPattern.MatchString(NormalizeSKU(sku))
+}
+
+// IsValidVariantOption checks a normalized option name and value.
+func IsValidVariantOption(name, value string) bool {
+	return variantOptionPattern.MatchString(name) && value != "" &&
+		utf8.RuneCountInString(value) <= variantOptionMaxValue
+}
+
+func (v *Variant) Validate() error {
+	if v.ID.String() == "" {
+		return ErrIDIsRequired
+	}
+	if _, err := entity.ParseID(v.ID.String()); err != nil {
+		return ErrInvalidID
+	}
+	if _, err := entity.ParseID(v.ProductID.String()); err != nil {
+		return ErrInvalidID
+	}
+	if !skuPattern.MatchString(v.SKU) {
+		return ErrInvalidSKU
+	}
+	if len(v.Options) == 0 || len(v.Options) > VariantMaxOptions {
+		return ErrInvalidVariantOptions
+	}
+	for name, value := range v.Options {
+		if !IsValidVariantOption(name, value) {
+			return ErrInvalidVariantOptions
+		}
+	}
+	if v.Price == nil {
+		return nil
+	}
+	if v.Price.IsZero() {
+		return ErrPriceIsRequired
+	}
+	if v.Price.IsNegative() {
+		return ErrInvalidPrice
+	}
+	if !entity.IsValidCurrency(v.Price.Currency) {
+		return entity.ErrInvalidCurrency
+	}
+	return nil
+}
+
+// HasOptions reports whether the variant has exactly the given options.
+func (v *Variant) HasOptions(options map[string]string) bool {
+	if len(v.Options) != len(options) {
+		return false
+	}
+	for name, value := range options {
+		if current, ok := v.Options[name]; !ok || current != value {
+			return false
+		}
+	}
+	return true
+}
+
+// EffectivePrice is the price the variant sells for: its own price or the
+// product's.
+func (v *Variant) EffectivePrice(product *Product) entity.Money {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return product.Price
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVariant(t *testing.T) {
+	productID := entity.NewID()
+	v, err := NewVariant(productID, " shirt-m-red ", map[string]string{" Size ": " M ", "color": "red"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "SHIRT-M-RED", v.SKU)
+	assert.Equal(t, map[string]string{"size": "M", "color": "red"}, v.Options)
+	assert.Nil(t, v.Price)
+
+	price := entity.Money{Amount: 1500, Currency: "BRL"}
+	v, err = NewVariant(productID, "SHIRT-XL", map[string]string{"size": "XL"}, &price)
+	assert.Nil(t, err)
+	assert.Equal(t, price, *v.Price)
+}
+
+func TestVariant_Validate(t *testing.T) {
+	productID := entity.NewID()
+	size := map[string]string{"size": "M"}
+	_, err := NewVariant(productID, "", size, nil)
+	assert.Equal(t, ErrInvalidSKU, err)
+	_, err = NewVariant(productID, "SHIRT M", size, nil)
+	assert.Equal(t, ErrInvalidSKU, err)
+	_, err = NewVariant(productID, "SHIRT-M", nil, nil)
+	assert.Equal(t, ErrInvalidVariantOptions, err)
+	_, err = NewVariant(productID, "SHIRT-M", map[string]string{"size": " "}, nil)
+	assert.Equal(t, ErrInvalidVariantOptions, err)
+	_, err = NewVariant(productID, "SHIRT-M", map[string]string{"1size": "M"}, nil)
+	assert.Equal(t, ErrInvalidVariantOptions, err)
+
+	_, err = NewVariant(productID, "SHIRT-M", size, &entity.Money{Currency: "BRL"})
+	assert.Equal(t, ErrPriceIsRequired, err)
+	_, err = NewVariant(productID, "SHIRT-M", size, &entity.Money{Amount: -1, Currency: "BRL"})
+	assert.Equal(t, ErrInvalidPrice, err)
+	_, err = NewVariant(productID, "SHIRT-M", size, &entity.Money{Amount: 1, Currency: "XYZ"})
+	assert.Equal(t, entity.ErrInvalidCurrency, err)
+}
+
+func TestVariant_HasOptions(t *testing.T) {
+	v, _ := NewVariant(entity.NewID(), "SHIRT-M-RED", map[string]string{"size": "M", "color": "red"}, nil)
+	assert.True(t, v.HasOptions(map[string]string{"color": "red", "size": "M"}))
+	assert.False(t, v.HasOptions(map[string]string{"size": "M"}))
+	assert.False(t, v.HasOptions(map[string]string{"size": "M", "color": "blue"}))
+}
+
+func TestVariant_EffectivePrice(t *testing.T) {
+	product, _ := NewProduct("Shirt", entity.Money{Amount: 1000, Currency: "BRL"})
+	v, _ := NewVariant(product.ID, "SHIRT-M", map[string]string{"size": "M"}, nil)
+	assert.Equal(t, product.Price, v.EffectivePrice(product))
+
+	price := entity.Money{Amount: 1200, Currency: "BRL"}
+	v.Price = &price
+	assert.Equal(t, price, v.EffectivePrice(product))
+}
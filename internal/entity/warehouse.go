@@ -0,0 +1,142 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+var (
+	ErrInvalidWarehouseCode = errors.New("warehouse code must have 1 to 16 letters, digits or dashes")
+	ErrWarehouseCodeExists  = errors.New("warehouse code already exists")
+	ErrWarehouseNotFound    = errors.New("warehouse not found")
+	ErrSameWarehouse        = errors.New("transfer source and destination must be different warehouses")
+)
+
+var warehouseCodePattern = regexp.MustCompile(`^[A-Z0-9-]{1,16}$`)
+
+// Movement reasons used by transfers; they cannot be used for adjustments.
+const (
+	StockReasonTransferOut = "transfer_out"
+	StockReasonTransferIn  = "transfer_in"
+)
+
+type Warehouse struct {
+	ID        entity.ID `json:"id"`
+	Code      string    `json:"code" example:"SP-01"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewWarehouse upper-cases code, which identifies the warehouse to people.
+func NewWarehouse(code, name string) (*Warehouse, error) {
+	warehouse := &Warehouse{
+		ID:        entity.NewID(),
+		Code:      strings.ToUpper(code),
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	err := warehouse.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+func (w *Warehouse) Validate() error {
+	if w.ID.String() == "" {
+		return ErrIDIsRequired
+	}
+	if _, err := entity.ParseID(w.ID.String()); err != nil {
+		return ErrInvalidID
+	}
+	if w.Code != strings.ToUpper(w.Code) || !IsValidWarehouseCode(w.Code) {
+		return ErrInvalidWarehouseCode
+	}
+	if w.Name == "" {
+		return ErrNameIsRequired
+	}
+	return nil
+}
+
+// IsValidWarehouseCode accepts codes in any case; they are stored upper-case.
+func IsValidWarehouseCode(code string) bool {
+	return warehouseCodePattern.MatchString(strings.ToUpper(code))
+}
+
+// WarehouseStock is the quantity of a product physically held in a
+// warehouse.
+type WarehouseStock struct {
+	ProductID   entity.ID `json:"product_id" gorm:"primaryKey"`
+	WarehouseID entity.ID `json:"warehouse_id" gorm:"primaryKey"`
+	Quantity    int64     `json:"quantity"`
+}
+
+func (WarehouseStock) TableName() string {
+	return "warehouse_stock"
+}
+
+// StockLocation is a product's quantity in one warehouse, with the
+// warehouse's details.
+type StockLocation struct {
+	WarehouseID entity.ID `json:"warehouse_id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Quantity    int64     `json:"quantity"`
+}
+
+// StockMovement records a change to a product's quantity in a warehouse.
+// The two movements of a transfer share a TransferID.
+type StockMovement struct {
+	ID          entity.ID  `json:"id"`
+	ProductID   entity.ID  `json:"product_id"`
+	WarehouseID entity.ID  `json:"warehouse_id"`
+	Delta       int64      `json:"delta"`
+	Reason      string     `json:"reason"`
+	TransferID  *entity.ID `json:"transfer_id,omitempty"`
+	ActorID     *entity.ID `json:"actor_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newStockMovement(productID, warehouseID entity.ID, delta int64, reason string, transferID, actorID *entity.ID, at time.Time) StockMovement {
+	return StockMovement{
+		ID:          entity.NewID(),
+		ProductID:   productID,
+		WarehouseID: warehouseID,
+		Delta:       delta,
+		Reason:      reason,
+		TransferID:  transferID,
+		ActorID:     actorID,
+		CreatedAt:   at,
+	}
+}
+
+// Movement is the warehouse movement matching an adjustment made at a
+// warehouse, or nil when the adjustment has no warehouse.
+func (a *StockAdjustment) Movement() *StockMovement {
+	if a.WarehouseID == nil {
+		return nil
+	}
+	movement := newStockMovement(a.ProductID, *a.WarehouseID, a.Delta, a.Reason, nil, a.ActorID, a.CreatedAt)
+	return &movement
+}
+
+// NewStockTransfer returns the paired movements that take quantity out of
+// one warehouse and into another.
+func NewStockTransfer(productID, from, to entity.ID, quantity int64, actorID *entity.ID) ([2]StockMovement, error) {
+	if quantity <= 0 {
+		return [2]StockMovement{}, ErrInvalidQuantity
+	}
+	if from == to {
+		return [2]StockMovement{}, ErrSameWarehouse
+	}
+	transferID := entity.NewID()
+	now := time.Now()
+	return [2]StockMovement{
+		newStockMovement(productID, from, -quantity, StockReasonTransferOut, &transferID, actorID, now),
+		newStockMovement(productID, to, quantity, StockReasonTransferIn, &transferID, actorID, now),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWarehouse(t *testing.T) {
+	w, err := NewWarehouse("sp-01", "São Paulo")
+	assert.Nil(t, err)
+	assert.Equal(t, "SP-01", w.Code)
+
+	_, err = NewWarehouse("sp 01", "São Paulo")
+	assert.Equal(t, ErrInvalidWarehouseCode, err)
+	_, err = NewWarehouse("SP-01", "")
+	assert.Equal(t, ErrNameIsRequired, err)
+}
+
+func TestNewStockTransfer(t *testing.T) {
+	productID, from, to := entity.NewID(), entity.NewID(), entity.NewID()
+	movements, err := NewStockTransfer(productID, from, to, 3, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, from, movements[0].WarehouseID)
+	assert.Equal(t, int64(-3), movements[0].Delta)
+	assert.Equal(t, StockReasonTransferOut, movements[0].Reason)
+	assert.Equal(t, to, movements[1].WarehouseID)
+	assert.Equal(t, int64(3), movements[1].Delta)
+	assert.Equal(t, movements[0].TransferID, movements[1].TransferID)
+
+	_, err = NewStockTransfer(productID, from, from, 3, nil)
+	assert.Equal(t, ErrSameWarehouse, err)
+	_, err = NewStockTransfer(productID, from, to, 0, nil)
+	assert.Equal(t, ErrInvalidQuantity, err)
+}
+
+func TestStockAdjustment_Movement(t *testing.T) {
+	a, _ := NewStockAdjustment(entity.NewID(), nil, 2, StockReasonReceived, "", nil)
+	assert.Nil(t, a.Movement())
+
+	warehouseID := entity.NewID()
+	a, _ = NewStockAdjustment(entity.NewID(), &warehouseID, 2, StockReasonReceived, "", nil)
+	movement := a.Movement()
+	assert.Equal(t, warehouseID, movement.WarehouseID)
+	assert.Equal(t, int64(2), movement.Delta)
+	assert.Nil(t, movement.TransferID)
+}
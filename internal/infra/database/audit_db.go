@@ -0,0 +1,82 @@
+package database
+
+import (
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+// AuditQuery filters the audit log. Zero values mean "no filter"; entries
+// come newest first.
+type AuditQuery struct {
+	Page       int
+	Limit      int
+	ActorID    string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+func (q AuditQuery) Normalize() AuditQuery {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 10 {
+		q.Limit = 10
+	}
+	return q
+}
+
+func (q AuditQuery) filter(db *gorm.DB) *gorm.DB {
+	if q.ActorID != "" {
+		db = db.Where("actor_id = ?", q.ActorID)
+	}
+	if q.EntityType != "" {
+		db = db.Where("entity_type = ?", q.EntityType)
+	}
+	if q.EntityID != "" {
+		db = db.Where("entity_id = ?", q.EntityID)
+	}
+	if q.From != nil {
+		db = db.Where("created_at >= ?", *q.From)
+	}
+	if q.To != nil {
+		db = db.Where("created_at < ?", *q.To)
+	}
+	return db
+}
+
+// Audit is append-only: it offers no way to change or remove entries.
+type Audit struct {
+	DB *gorm.DB
+}
+
+func (a *Audit) Record(entry *entity.AuditEntry) error {
+	return a.DB.Create(entry).Error
+}
+
+func (a *Audit) FindAll(query AuditQuery) ([]entity.AuditEntry, int64, error) {
+	query = query.Normalize()
+
+	var total int64
+	err := query.filter(a.DB.Model(&entity.AuditEntry{})).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entity.AuditEntry
+	err = query.filter(a.DB).
+		Order("created_at DESC").Order("id DESC").
+		Offset((query.Page - 1) * query.Limit).Limit(query.Limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func NewAudit(db *gorm.DB) *Audit {
+	return &Audit{DB: db}
+}
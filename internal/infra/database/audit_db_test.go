@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAudit_FindAll(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.AuditEntry{})
+	auditDB := NewAudit(db)
+
+	actorID := pkgEntity.NewID()
+	productID := pkgEntity.NewID().String()
+	changes := entity.AuditChanges{"name": {From: "Product 1", To: "Product 2"}}
+	first := entity.NewAuditEntry(&actorID, entity.AuditActionCreate, "product", productID, nil)
+	first.CreatedAt = time.Now().Add(-2 * time.Hour)
+	assert.Nil(t, auditDB.Record(first))
+	assert.Nil(t, auditDB.Record(entity.NewAuditEntry(&actorID, entity.AuditActionUpdate, "product", productID, changes)))
+	assert.Nil(t, auditDB.Record(entity.NewAuditEntry(nil, entity.AuditActionCreate, "user", pkgEntity.NewID().String(), nil)))
+
+	entries, total, err := auditDB.FindAll(AuditQuery{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, entries, 3)
+
+	entries, total, err = auditDB.FindAll(AuditQuery{ActorID: actorID.String(), EntityType: "product", EntityID: productID})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, entity.AuditActionUpdate, entries[0].Action)
+	assert.Equal(t, changes, entries[0].Changes)
+	assert.Equal(t, &actorID, entries[0].ActorID)
+
+	from := time.Now().Add(-time.Hour)
+	_, total, err = auditDB.FindAll(AuditQuery{EntityType: "product", From: &from})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+}
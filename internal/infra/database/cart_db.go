@@ -0,0 +1,194 @@
+package database
+
+import (
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// cartItem is the stored form of entity.CartItem; the previous price is kept
+// in nullable columns like the variant price.
+type cartItem struct {
+	UserID                string `gorm:"primaryKey"`
+	ProductID             string `gorm:"primaryKey"`
+	Name                  string
+	Quantity              int64
+	UnitPriceAmount       int64
+	UnitPriceCurrency     string
+	PreviousPriceAmount   *int64
+	PreviousPriceCurrency *string
+	Status                string
+	AddedAt               time.Time
+}
+
+func newCartItem(userID string, item *entity.CartItem) cartItem {
+	row := cartItem{
+		UserID:            userID,
+		ProductID:         item.ProductID.String(),
+		Name:              item.Name,
+		Quantity:          item.Quantity,
+		UnitPriceAmount:   item.UnitPrice.Amount,
+		UnitPriceCurrency: item.UnitPrice.Currency,
+		Status:            item.Status,
+		AddedAt:           item.AddedAt,
+	}
+	if item.PreviousPrice != nil {
+		row.PreviousPriceAmount = &item.PreviousPrice.Amount
+		row.PreviousPriceCurrency = &item.PreviousPrice.Currency
+	}
+	return row
+}
+
+func (row cartItem) item() (entity.CartItem, error) {
+	productID, err := pkgEntity.ParseID(row.ProductID)
+	if err != nil {
+		return entity.CartItem{}, err
+	}
+	item := entity.CartItem{
+		ProductID: productID,
+		Name:      row.Name,
+		Quantity:  row.Quantity,
+		UnitPrice: pkgEntity.Money{Amount: row.UnitPriceAmount, Currency: row.UnitPriceCurrency},
+		Status:    row.Status,
+		AddedAt:   row.AddedAt,
+	}
+	if row.PreviousPriceAmount != nil && row.PreviousPriceCurrency != nil {
+		item.PreviousPrice = &pkgEntity.Money{Amount: *row.PreviousPriceAmount, Currency: *row.PreviousPriceCurrency}
+	}
+	return item, nil
+}
+
+type Cart struct {
+	DB *gorm.DB
+}
+
+// FindItems lists the items of a user's cart in the order they were added.
+func (c *Cart) FindItems(userID string) ([]entity.CartItem, error) {
+	var rows []cartItem
+	err := c.DB.Where("user_id = ?", userID).Order("added_at, product_id").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	items := make([]entity.CartItem, len(rows))
+	for i, row := range rows {
+		if items[i], err = row.item(); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// AddItem puts item in a user's cart, or adds its quantity to the item
+// already there and refreshes its snapshots. The user's row is locked while
+// the limits are checked, so concurrent adds cannot take the cart past
+// entity.MaxCartItems products, and the quantity is summed by the database
+// so that no add is lost.
+func (c *Cart) AddItem(userID string, item *entity.CartItem) error {
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", userID).
+			Find(&[]entity.User{}).Error
+		if err != nil {
+			return err
+		}
+		row := newCartItem(userID, item)
+		var current []cartItem
+		err = tx.Where("user_id = ? AND product_id = ?", row.UserID, row.ProductID).Find(&current).Error
+		if err != nil {
+			return err
+		}
+		if len(current) == 0 {
+			var count int64
+			if err := tx.Model(&cartItem{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= entity.MaxCartItems {
+				return entity.ErrCartFull
+			}
+		} else if current[0].Quantity+row.Quantity > entity.MaxCartItemQuantity {
+			return entity.ErrInvalidQuantity
+		}
+		updates := clause.AssignmentColumns([]string{
+			"name", "unit_price_amount", "unit_price_currency",
+			"previous_price_amount", "previous_price_currency", "status",
+		})
+		updates = append(updates, clause.Assignment{
+			Column: clause.Column{Name: "quantity"},
+			Value:  gorm.Expr("cart_items.quantity + ?", row.Quantity),
+		})
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+			DoUpdates: updates,
+		}).Create(&row).Error
+	})
+}
+
+// SaveItems writes back items already in a user's cart, as changed by
+// revalidation or by a new quantity.
+func (c *Cart) SaveItems(userID string, items []entity.CartItem) error {
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range items {
+			if err := updateCartItem(tx, newCartItem(userID, &items[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RevalidateProduct brings the items of a product up to date with it in
+// every cart, marking them unavailable while the product is in the trash.
+// It is meant to run in the transaction that changed the product.
+func (c *Cart) RevalidateProduct(productID string) error {
+	var products []entity.Product
+	if err := c.DB.Where("id = ?", productID).Find(&products).Error; err != nil {
+		return err
+	}
+	var product *entity.Product
+	if len(products) > 0 {
+		product = &products[0]
+	}
+	var rows []cartItem
+	if err := c.DB.Where("product_id = ?", productID).Find(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		item, err := row.item()
+		if err != nil {
+			return err
+		}
+		if !item.Revalidate(product) {
+			continue
+		}
+		if err := updateCartItem(c.DB, newCartItem(row.UserID, &item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateCartItem(db *gorm.DB, row cartItem) error {
+	return db.Model(&cartItem{}).Where("user_id = ? AND product_id = ?", row.UserID, row.ProductID).
+		Select("*").Omit("user_id", "product_id", "added_at").Updates(&row).Error
+}
+
+func (c *Cart) RemoveItem(userID, productID string) error {
+	result := c.DB.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&cartItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (c *Cart) Clear(userID string) error {
+	return c.DB.Where("user_id = ?", userID).Delete(&cartItem{}).Error
+}
+
+func NewCart(db *gorm.DB) *Cart {
+	return &Cart{DB: db}
+}
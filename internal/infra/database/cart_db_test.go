@@ -0,0 +1,144 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCart_AddItem(t *testing.T) {
+	db := newProductTestDB(t)
+	cartDB := NewCart(db)
+	userID := pkgEntity.NewID().String()
+
+	shirt, _ := entity.NewProduct("Shirt", pkgEntity.Money{Amount: 1000, Currency: "BRL"})
+	mug, _ := entity.NewProduct("Mug", pkgEntity.Money{Amount: 500, Currency: "BRL"})
+	shirtItem, _ := entity.NewCartItem(shirt, 1)
+	mugItem, _ := entity.NewCartItem(mug, 2)
+	mugItem.AddedAt = shirtItem.AddedAt.Add(1)
+	assert.Nil(t, cartDB.AddItem(userID, shirtItem))
+	assert.Nil(t, cartDB.AddItem(userID, mugItem))
+
+	shirt.Price.Amount = 1200
+	moreShirts, _ := entity.NewCartItem(shirt, 2)
+	assert.Nil(t, cartDB.AddItem(userID, moreShirts))
+
+	items, err := cartDB.FindItems(userID)
+	assert.Nil(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, shirt.ID, items[0].ProductID)
+	assert.Equal(t, int64(3), items[0].Quantity)
+	assert.Equal(t, int64(1200), items[0].UnitPrice.Amount)
+	assert.Equal(t, shirtItem.AddedAt.Unix(), items[0].AddedAt.Unix())
+	assert.Nil(t, items[1].PreviousPrice)
+
+	tooMany, _ := entity.NewCartItem(shirt, entity.MaxCartItemQuantity)
+	assert.Equal(t, entity.ErrInvalidQuantity, cartDB.AddItem(userID, tooMany))
+
+	other, err := cartDB.FindItems(pkgEntity.NewID().String())
+	assert.Nil(t, err)
+	assert.Empty(t, other)
+}
+
+func TestCart_AddItem_Full(t *testing.T) {
+	db := newProductTestDB(t)
+	cartDB := NewCart(db)
+	userID := pkgEntity.NewID().String()
+
+	var first *entity.CartItem
+	for i := 0; i < entity.MaxCartItems; i++ {
+		product, _ := entity.NewProduct("Product", pkgEntity.Money{Amount: 100, Currency: "BRL"})
+		item, _ := entity.NewCartItem(product, 1)
+		assert.Nil(t, cartDB.AddItem(userID, item))
+		if first == nil {
+			first = item
+		}
+	}
+	product, _ := entity.NewProduct("Product", pkgEntity.Money{Amount: 100, Currency: "BRL"})
+	item, _ := entity.NewCartItem(product, 1)
+	assert.Equal(t, entity.ErrCartFull, cartDB.AddItem(userID, item))
+	assert.Nil(t, cartDB.AddItem(userID, first))
+}
+
+func TestCart_SaveItems(t *testing.T) {
+	db := newProductTestDB(t)
+	cartDB := NewCart(db)
+	userID := pkgEntity.NewID().String()
+
+	shirt, _ := entity.NewProduct("Shirt", pkgEntity.Money{Amount: 1000, Currency: "BRL"})
+	shirtItem, _ := entity.NewCartItem(shirt, 1)
+	cartDB.AddItem(userID, shirtItem)
+
+	shirt.Price.Amount = 1200
+	items, _ := cartDB.FindItems(userID)
+	items[0].Revalidate(shirt)
+	assert.Nil(t, cartDB.SaveItems(userID, items))
+	assert.Nil(t, cartDB.SaveItems(userID, items), "an unchanged item still saves")
+	items, _ = cartDB.FindItems(userID)
+	assert.Equal(t, int64(1200), items[0].UnitPrice.Amount)
+	assert.Equal(t, int64(1000), items[0].PreviousPrice.Amount)
+	assert.Equal(t, entity.CartItemPriceChanged, items[0].Status)
+
+	items[0].SetQuantity(3)
+	assert.Nil(t, cartDB.SaveItems(userID, items))
+	items, _ = cartDB.FindItems(userID)
+	assert.Equal(t, int64(3), items[0].Quantity)
+	assert.Nil(t, items[0].PreviousPrice)
+	assert.Equal(t, entity.CartItemAvailable, items[0].Status)
+}
+
+func TestCart_RevalidateProduct(t *testing.T) {
+	db := newProductTestDB(t)
+	cartDB := NewCart(db)
+	productDB := NewProduct(db)
+	shirt, _ := entity.NewProduct("Shirt", pkgEntity.Money{Amount: 1000, Currency: "BRL"})
+	productDB.Create(shirt)
+	users := []string{pkgEntity.NewID().String(), pkgEntity.NewID().String()}
+	for _, userID := range users {
+		item, _ := entity.NewCartItem(shirt, 1)
+		cartDB.AddItem(userID, item)
+	}
+
+	shirt.Price.Amount = 1200
+	productDB.Update(shirt, "")
+	assert.Nil(t, cartDB.RevalidateProduct(shirt.ID.String()))
+	for _, userID := range users {
+		items, _ := cartDB.FindItems(userID)
+		assert.Equal(t, entity.CartItemPriceChanged, items[0].Status)
+		assert.Equal(t, int64(1000), items[0].PreviousPrice.Amount)
+	}
+
+	productDB.Delete(shirt.ID.String(), shirt.Version)
+	assert.Nil(t, cartDB.RevalidateProduct(shirt.ID.String()))
+	items, _ := cartDB.FindItems(users[0])
+	assert.Equal(t, entity.CartItemUnavailable, items[0].Status)
+
+	assert.Nil(t, productDB.Purge(shirt.ID.String()))
+	items, _ = cartDB.FindItems(users[1])
+	assert.Empty(t, items)
+}
+
+func TestCart_RemoveItem(t *testing.T) {
+	db := newProductTestDB(t)
+	cartDB := NewCart(db)
+	userID := pkgEntity.NewID().String()
+
+	shirt, _ := entity.NewProduct("Shirt", pkgEntity.Money{Amount: 1000, Currency: "BRL"})
+	mug, _ := entity.NewProduct("Mug", pkgEntity.Money{Amount: 500, Currency: "BRL"})
+	shirtItem, _ := entity.NewCartItem(shirt, 1)
+	mugItem, _ := entity.NewCartItem(mug, 1)
+	cartDB.AddItem(userID, shirtItem)
+	cartDB.AddItem(userID, mugItem)
+
+	assert.Equal(t, gorm.ErrRecordNotFound, cartDB.RemoveItem(pkgEntity.NewID().String(), shirt.ID.String()))
+	assert.Nil(t, cartDB.RemoveItem(userID, shirt.ID.String()))
+	items, _ := cartDB.FindItems(userID)
+	assert.Len(t, items, 1)
+
+	assert.Nil(t, cartDB.Clear(userID))
+	items, _ = cartDB.FindItems(userID)
+	assert.Empty(t, items)
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+// categorySubtree selects the ID of a category and of every category below
+// it. Recursive CTEs are understood by sqlite, mysql 8 and postgres.
+const categorySubtree = `WITH RECURSIVE subtree(id) AS (
+	SELECT id FROM categories WHERE id = ?
+	UNION ALL
+	SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+) SELECT id FROM subtree`
+
+// productCategory links a product to one of its categories.
+type productCategory struct {
+	ProductID  string `gorm:"primaryKey"`
+	CategoryID string `gorm:"primaryKey"`
+}
+
+func (productCategory) TableName() string {
+	return "product_categories"
+}
+
+type Category struct {
+	DB *gorm.DB
+}
+
+func (c *Category) Create(category *entity.Category) error {
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkParent(tx, category); err != nil {
+			return err
+		}
+		return tx.Create(category).Error
+	})
+}
+
+// FindAll returns every category ordered by name; callers build the tree
+// from ParentID.
+func (c *Category) FindAll() ([]entity.Category, error) {
+	var categories []entity.Category
+	err := c.DB.Order("name").Order("id").Find(&categories).Error
+	return categories, err
+}
+
+func (c *Category) FindByID(id string) (*entity.Category, error) {
+	var category entity.Category
+	err := c.DB.Where("id = ?", id).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Update renames or moves the category, refusing to put it below one of its
+// own subcategories.
+func (c *Category) Update(category *entity.Category) error {
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		var current entity.Category
+		if err := tx.Where("id = ?", category.ID).First(&current).Error; err != nil {
+			return err
+		}
+		if err := checkParent(tx, category); err != nil {
+			return err
+		}
+		if category.ParentID != nil {
+			var ids []string
+			if err := tx.Raw(categorySubtree, category.ID).Scan(&ids).Error; err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if id == category.ParentID.String() {
+					return entity.ErrCategoryCycle
+				}
+			}
+		}
+		return tx.Model(&entity.Category{}).Where("id = ?", category.ID).
+			Updates(map[string]interface{}{"name": category.Name, "parent_id": category.ParentID}).Error
+	})
+}
+
+// Delete removes a category without subcategories along with its links to
+// products; the products themselves are kept.
+func (c *Category) Delete(id string) error {
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		var children int64
+		if err := tx.Model(&entity.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return entity.ErrCategoryHasChildren
+		}
+		if err := tx.Where("category_id = ?", id).Delete(&productCategory{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&entity.Category{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (c *Category) FindByProduct(productID string) ([]entity.Category, error) {
+	var categories []entity.Category
+	err := c.DB.
+		Joins("JOIN product_categories ON product_categories.category_id = categories.id").
+		Where("product_categories.product_id = ?", productID).
+		Order("categories.name").Order("categories.id").
+		Find(&categories).Error
+	return categories, err
+}
+
+// SetProductCategories replaces the categories of a product. Every ID must
+// belong to an existing category.
+func (c *Category) SetProductCategories(productID string, categoryIDs []string) error {
+	unique := map[string]bool{}
+	for _, id := range categoryIDs {
+		unique[id] = true
+	}
+	return c.DB.Transaction(func(tx *gorm.DB) error {
+		if len(unique) > 0 {
+			var found int64
+			err := tx.Model(&entity.Category{}).Where("id IN ?", categoryIDs).Count(&found).Error
+			if err != nil {
+				return err
+			}
+			if found != int64(len(unique)) {
+				return entity.ErrCategoryNotFound
+			}
+		}
+		if err := tx.Where("product_id = ?", productID).Delete(&productCategory{}).Error; err != nil {
+			return err
+		}
+		links := make([]productCategory, 0, len(unique))
+		for id := range unique {
+			links = append(links, productCategory{ProductID: productID, CategoryID: id})
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.Create(&links).Error
+	})
+}
+
+func checkParent(tx *gorm.DB, category *entity.Category) error {
+	if category.ParentID == nil {
+		return nil
+	}
+	var count int64
+	err := tx.Model(&entity.Category{}).Where("id = ?", *category.ParentID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return entity.ErrCategoryNotFound
+	}
+	return nil
+}
+
+func NewCategory(db *gorm.DB) *Category {
+	return &Category{DB: db}
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newCategoryDB(t *testing.T) *Category {
+	return NewCategory(newProductTestDB(t))
+}
+
+func TestCategory_CreateChecksParent(t *testing.T) {
+	categoryDB := newCategoryDB(t)
+	root, _ := entity.NewCategory("Clothing", nil)
+	assert.Nil(t, categoryDB.Create(root))
+	child, _ := entity.NewCategory("Shirts", &root.ID)
+	assert.Nil(t, categoryDB.Create(child))
+
+	missing := pkgEntity.NewID()
+	orphan, _ := entity.NewCategory("Shoes", &missing)
+	assert.Equal(t, entity.ErrCategoryNotFound, categoryDB.Create(orphan))
+
+	categories, err := categoryDB.FindAll()
+	assert.Nil(t, err)
+	assert.Len(t, categories, 2)
+	assert.Equal(t, "Clothing", categories[0].Name)
+	assert.Equal(t, &root.ID, categories[1].ParentID)
+}
+
+func TestCategory_UpdateRejectsCycle(t *testing.T) {
+	categoryDB := newCategoryDB(t)
+	root, _ := entity.NewCategory("Clothing", nil)
+	child, _ := entity.NewCategory("Shirts", &root.ID)
+	grandchild, _ := entity.NewCategory("T-Shirts", &child.ID)
+	for _, c := range []*entity.Category{root, child, grandchild} {
+		assert.Nil(t, categoryDB.Create(c))
+	}
+
+	root.ParentID = &grandchild.ID
+	assert.Equal(t, entity.ErrCategoryCycle, categoryDB.Update(root))
+
+	grandchild.ParentID = nil
+	grandchild.Name = "Tees"
+	assert.Nil(t, categoryDB.Update(grandchild))
+	found, err := categoryDB.FindByID(grandchild.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, "Tees", found.Name)
+	assert.Nil(t, found.ParentID)
+
+	missing, _ := entity.NewCategory("Shoes", nil)
+	assert.Equal(t, gorm.ErrRecordNotFound, categoryDB.Update(missing))
+}
+
+func TestCategory_Delete(t *testing.T) {
+	categoryDB := newCategoryDB(t)
+	root, _ := entity.NewCategory("Clothing", nil)
+	child, _ := entity.NewCategory("Shirts", &root.ID)
+	assert.Nil(t, categoryDB.Create(root))
+	assert.Nil(t, categoryDB.Create(child))
+	product, _ := entity.NewProduct("Blue Shirt", brl(100))
+	categoryDB.DB.Create(product)
+	assert.Nil(t, categoryDB.SetProductCategories(product.ID.String(), []string{child.ID.String()}))
+
+	assert.Equal(t, entity.ErrCategoryHasChildren, categoryDB.Delete(root.ID.String()))
+	assert.Nil(t, categoryDB.Delete(child.ID.String()))
+	assert.Equal(t, gorm.ErrRecordNotFound, categoryDB.Delete(child.ID.String()))
+
+	categories, err := categoryDB.FindByProduct(product.ID.String())
+	assert.Nil(t, err)
+	assert.Len(t, categories, 0)
+}
+
+func TestCategory_SetProductCategories(t *testing.T) {
+	categoryDB := newCategoryDB(t)
+	shirts, _ := entity.NewCategory("Shirts", nil)
+	sale, _ := entity.NewCategory("Sale", nil)
+	assert.Nil(t, categoryDB.Create(shirts))
+	assert.Nil(t, categoryDB.Create(sale))
+	productID := pkgEntity.NewID().String()
+
+	err := categoryDB.SetProductCategories(productID, []string{shirts.ID.String(), sale.ID.String(), shirts.ID.String()})
+	assert.Nil(t, err)
+	categories, err := categoryDB.FindByProduct(productID)
+	assert.Nil(t, err)
+	assert.Len(t, categories, 2)
+	assert.Equal(t, "Sale", categories[0].Name)
+
+	err = categoryDB.SetProductCategories(productID, []string{shirts.ID.String(), pkgEntity.NewID().String()})
+	assert.Equal(t, entity.ErrCategoryNotFound, err)
+	categories, _ = categoryDB.FindByProduct(productID)
+	assert.Len(t, categories, 2)
+
+	assert.Nil(t, categoryDB.SetProductCategories(productID, nil))
+	categories, _ = categoryDB.FindByProduct(productID)
+	assert.Len(t, categories, 0)
+}
+
+func TestProduct_FindAll_ByCategory(t *testing.T) {
+	categoryDB := newCategoryDB(t)
+	clothing, _ := entity.NewCategory("Clothing", nil)
+	shirts, _ := entity.NewCategory("Shirts", &clothing.ID)
+	tees, _ := entity.NewCategory("T-Shirts", &shirts.ID)
+	for _, c := range []*entity.Category{clothing, shirts, tees} {
+		assert.Nil(t, categoryDB.Create(c))
+	}
+	for name, category := range map[string]*entity.Category{"Jacket": clothing, "Oxford": shirts, "Plain Tee": tees} {
+		product, _ := entity.NewProduct(name, brl(100))
+		categoryDB.DB.Create(product)
+		assert.Nil(t, categoryDB.SetProductCategories(product.ID.String(), []string{category.ID.String()}))
+	}
+	productDB := NewProduct(categoryDB.DB)
+
+	products, total, err := productDB.FindAll(ProductQuery{Category: shirts.ID.String()})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "Oxford", products[0].Name)
+
+	products, total, err = productDB.FindAll(ProductQuery{Category: shirts.ID.String(), IncludeDescendants: true, SortBy: "name"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "Oxford", products[0].Name)
+	assert.Equal(t, "Plain Tee", products[1].Name)
+
+	_, total, err = productDB.FindAll(ProductQuery{Category: clothing.ID.String(), IncludeDescendants: true})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var ErrUnsupportedDriver = errors.New("unsupported database driver")
+
+type ConnectionConfig struct {
+	Driver          string
+	Host            string
+	Port            string
+	User            string
+	Password        string
+	Name            string
+	SSLMode         string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	PingRetries     int
+	PingBackoff     time.Duration
+}
+
+func DSN(conf ConnectionConfig) (string, error) {
+	switch conf.Driver {
+	case "sqlite", "sqlite3":
+		if conf.Name == "" || conf.Name == ":memory:" {
+			return "file::memory:?cache=shared", nil
+		}
+		return conf.Name, nil
+	case "mysql":
+		// ClientFoundRows makes an UPDATE report the rows it matched, like the
+		// other drivers, instead of only the rows whose values changed.
+		dsn := mysqlDriver.NewConfig()
+		dsn.User = conf.User
+		dsn.Passwd = conf.Password
+		dsn.Net = "tcp"
+		dsn.Addr = net.JoinHostPort(conf.Host, conf.Port)
+		dsn.DBName = conf.Name
+		dsn.Params = map[string]string{"charset": "utf8mb4"}
+		dsn.ParseTime = true
+		dsn.Loc = time.Local
+		dsn.ClientFoundRows = true
+		return dsn.FormatDSN(), nil
+	case "postgres", "postgresql":
+		sslMode := conf.SSLMode
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(conf.User, conf.Password),
+			Host:     net.JoinHostPort(conf.Host, conf.Port),
+			Path:     conf.Name,
+			RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+		}
+		return dsn.String(), nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedDriver, conf.Driver)
+}
+
+func Dialector(conf ConnectionConfig) (gorm.Dialector, error) {
+	dsn, err := DSN(conf)
+	if err != nil {
+		return nil, err
+	}
+	switch conf.Driver {
+	case "sqlite", "sqlite3":
+		return sqlite.Open(dsn), nil
+	case "mysql":
+		return mysql.Open(dsn), nil
+	default:
+		return postgres.Open(dsn), nil
+	}
+}
+
+func NewConnection(conf ConnectionConfig) (*gorm.DB, error) {
+	dialector, err := Dialector(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	var db *gorm.DB
+	err = retry(conf.PingRetries, conf.PingBackoff, func() error {
+		db, err = gorm.Open(dialector, &gorm.Config{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if conf.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(conf.MaxOpenConns)
+	}
+	if conf.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(conf.MaxIdleConns)
+	}
+	if conf.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(conf.ConnMaxLifetime)
+	}
+
+	err = retry(conf.PingRetries, conf.PingBackoff, sqlDB.Ping)
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// retry runs fn up to attempts times, doubling the wait between failures.
+func retry(attempts int, backoff time.Duration, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if i < attempts-1 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return fmt.Errorf("database unreachable after %d attempts: %w", attempts, err)
+}
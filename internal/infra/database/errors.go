@@ -0,0 +1,15 @@
+package database
+
+import "strings"
+
+// isUniqueViolation recognizes unique constraint errors from the sqlite,
+// mysql and postgres drivers, which share no common error type.
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") ||
+		strings.Contains(msg, "Duplicate entry") ||
+		strings.Contains(msg, "duplicate key value")
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"sync"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+)
+
+type exchangeRateEntry struct {
+	rate      *entity.ExchangeRate
+	expiresAt time.Time
+}
+
+// ExchangeRateCache keeps rates looked up from Store for TTL. Saves are
+// written through and drop the cached pair in both directions; rates saved
+// by another instance show up once the entry expires.
+type ExchangeRateCache struct {
+	Store ExchangeRateInterface
+	TTL   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]exchangeRateEntry
+}
+
+func (c *ExchangeRateCache) Save(rate *entity.ExchangeRate) error {
+	if err := c.Store.Save(rate); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, rate.Base+"/"+rate.Quote)
+	delete(c.entries, rate.Quote+"/"+rate.Base)
+	return nil
+}
+
+func (c *ExchangeRateCache) Find(base, quote string) (*entity.ExchangeRate, error) {
+	key := base + "/" + quote
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.rate, nil
+	}
+
+	rate, err := c.Store.Find(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = exchangeRateEntry{rate: rate, expiresAt: time.Now().Add(c.TTL)}
+	return rate, nil
+}
+
+func (c *ExchangeRateCache) FindAll() ([]entity.ExchangeRate, error) {
+	return c.Store.FindAll()
+}
+
+func NewExchangeRateCache(store ExchangeRateInterface, ttl time.Duration) *ExchangeRateCache {
+	return &ExchangeRateCache{
+		Store:   store,
+		TTL:     ttl,
+		entries: make(map[string]exchangeRateEntry),
+	}
+}
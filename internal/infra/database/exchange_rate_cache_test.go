@@ -0,0 +1,39 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchangeRateCache_Find(t *testing.T) {
+	exchangeRateDB := newExchangeRateDB(t)
+	cache := NewExchangeRateCache(exchangeRateDB, time.Minute)
+	rate, _ := entity.NewExchangeRate("USD", "BRL", "5")
+	assert.Nil(t, cache.Save(rate))
+
+	found, err := cache.Find("BRL", "USD")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.2", found.Rate)
+
+	// Changes made behind the cache's back are not seen until the TTL expires.
+	exchangeRateDB.DB.Model(&entity.ExchangeRate{}).Where("base = ?", "USD").Update("rate", "4")
+	found, _ = cache.Find("BRL", "USD")
+	assert.Equal(t, "0.2", found.Rate)
+
+	// Saving through the cache drops both directions of the pair.
+	rate, _ = entity.NewExchangeRate("USD", "BRL", "2")
+	assert.Nil(t, cache.Save(rate))
+	cache.TTL = 0
+	found, _ = cache.Find("BRL", "USD")
+	assert.Equal(t, "0.5", found.Rate)
+
+	exchangeRateDB.DB.Model(&entity.ExchangeRate{}).Where("base = ?", "USD").Update("rate", "4")
+	found, _ = cache.Find("BRL", "USD")
+	assert.Equal(t, "0.25", found.Rate)
+
+	_, err = cache.Find("BRL", "EUR")
+	assert.Equal(t, entity.ErrExchangeRateUnavailable, err)
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRate struct {
+	DB *gorm.DB
+}
+
+func (e *ExchangeRate) Save(rate *entity.ExchangeRate) error {
+	return e.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(rate).Error
+}
+
+// Find returns the base to quote rate, deriving it from the stored quote to
+// base rate when only that one exists.
+func (e *ExchangeRate) Find(base, quote string) (*entity.ExchangeRate, error) {
+	rate, err := e.find(base, quote)
+	if err == nil {
+		return rate, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	rate, err = e.find(quote, base)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entity.ErrExchangeRateUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rate.Inverse(), nil
+}
+
+func (e *ExchangeRate) find(base, quote string) (*entity.ExchangeRate, error) {
+	var rate entity.ExchangeRate
+	err := e.DB.Where("base = ? AND quote = ?", base, quote).First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (e *ExchangeRate) FindAll() ([]entity.ExchangeRate, error) {
+	var rates []entity.ExchangeRate
+	err := e.DB.Order("base").Order("quote").Find(&rates).Error
+	return rates, err
+}
+
+func NewExchangeRate(db *gorm.DB) *ExchangeRate {
+	return &ExchangeRate{DB: db}
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newExchangeRateDB(t *testing.T) *ExchangeRate {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.ExchangeRate{})
+	return NewExchangeRate(db)
+}
+
+func TestExchangeRate_Find(t *testing.T) {
+	exchangeRateDB := newExchangeRateDB(t)
+	rate, _ := entity.NewExchangeRate("USD", "BRL", "5")
+	assert.Nil(t, exchangeRateDB.Save(rate))
+
+	found, err := exchangeRateDB.Find("USD", "BRL")
+	assert.Nil(t, err)
+	assert.Equal(t, "5", found.Rate)
+
+	found, err = exchangeRateDB.Find("BRL", "USD")
+	assert.Nil(t, err)
+	assert.Equal(t, "0.2", found.Rate)
+	assert.Equal(t, "BRL", found.Base)
+
+	_, err = exchangeRateDB.Find("BRL", "EUR")
+	assert.Equal(t, entity.ErrExchangeRateUnavailable, err)
+}
+
+func TestExchangeRate_SaveReplaces(t *testing.T) {
+	exchangeRateDB := newExchangeRateDB(t)
+	rate, _ := entity.NewExchangeRate("USD", "BRL", "5")
+	assert.Nil(t, exchangeRateDB.Save(rate))
+	rate, _ = entity.NewExchangeRate("USD", "BRL", "4.9")
+	assert.Nil(t, exchangeRateDB.Save(rate))
+
+	rates, err := exchangeRateDB.FindAll()
+	assert.Nil(t, err)
+	assert.Len(t, rates, 1)
+	assert.Equal(t, "4.9", rates[0].Rate)
+}
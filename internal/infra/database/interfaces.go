@@ -1,16 +1,114 @@
 package database
 
-import "github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+import (
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
 
 type UserInterface interface {
 	Create(user *entity.User) error
 	FindByEmail(email string) (*entity.User, error)
+	FindByID(id string) (*entity.User, error)
+	UpdateRole(id, role string) error
 }
 
 type ProductInterface interface {
 	Create(product *entity.Product) error
-	FindAll(page, limit int, sort string) ([]entity.Product, error)
+	FindAll(query ProductQuery) ([]entity.Product, int64, error)
+	FindAfter(query ProductQuery, after *pkgEntity.Cursor) ([]entity.Product, *pkgEntity.Cursor, error)
+	Count(query ProductQuery) (int64, error)
 	FindByID(id string) (*entity.Product, error)
-	Update(product *entity.Product) error
+	FindByIDs(ids []string) ([]entity.Product, error)
+	Update(product *entity.Product, actorID string) error
+	UpdateColumns(product *entity.Product, actorID string, columns ...string) error
+	FindPriceHistory(id string, from, to *time.Time) ([]entity.PriceChange, error)
+	Delete(id string, version int) error
+	FindDeleted(query ProductQuery) ([]entity.Product, int64, error)
+	FindDeletedByID(id string) (*entity.Product, error)
+	FindAnyByID(id string) (*entity.Product, error)
+	Restore(id string) error
+	Purge(id string) error
+	AddTags(id string, tags []string) error
+	RemoveTags(id string, tags []string) error
+	FindTags() ([]entity.TagUsage, error)
+}
+
+type RefreshTokenInterface interface {
+	Create(token *entity.RefreshToken) error
+	FindByHash(hash string) (*entity.RefreshToken, error)
+	Rotate(current, next *entity.RefreshToken) error
+	RevokeFamily(familyID string) error
+}
+
+type RevokedTokenInterface interface {
+	Revoke(jti string, expiresAt time.Time) error
+	IsRevoked(jti string) (bool, error)
+}
+
+type ExchangeRateInterface interface {
+	Save(rate *entity.ExchangeRate) error
+	Find(base, quote string) (*entity.ExchangeRate, error)
+	FindAll() ([]entity.ExchangeRate, error)
+}
+
+type AuditInterface interface {
+	Record(entry *entity.AuditEntry) error
+	FindAll(query AuditQuery) ([]entity.AuditEntry, int64, error)
+}
+
+type CategoryInterface interface {
+	Create(category *entity.Category) error
+	FindAll() ([]entity.Category, error)
+	FindByID(id string) (*entity.Category, error)
+	Update(category *entity.Category) error
 	Delete(id string) error
+	FindByProduct(productID string) ([]entity.Category, error)
+	SetProductCategories(productID string, categoryIDs []string) error
+}
+
+type StockInterface interface {
+	Adjust(adjustment *entity.StockAdjustment) error
+	FindAdjustments(productID string) ([]entity.StockAdjustment, error)
+	Transfer(movements [2]entity.StockMovement) error
+	FindMovements(productID string) ([]entity.StockMovement, error)
+	FindLocations(productID string) ([]entity.StockLocation, error)
+	Reserve(reservation *entity.Reservation) error
+	FindReservation(id string) (*entity.Reservation, error)
+	Commit(id string) error
+	Release(id string) error
+	ReleaseExpired() (int, error)
+	Reserved(productID string) (int64, error)
+}
+
+type WarehouseInterface interface {
+	Create(warehouse *entity.Warehouse) error
+	FindAll() ([]entity.Warehouse, error)
+	FindByID(id string) (*entity.Warehouse, error)
+	Update(warehouse *entity.Warehouse) error
+}
+
+type VariantInterface interface {
+	Create(variant *entity.Variant) error
+	FindByProduct(productID string) ([]entity.Variant, error)
+	FindByID(productID, id string) (*entity.Variant, error)
+	Update(variant *entity.Variant) error
+	Delete(productID, id string) error
+}
+
+type ProductImageInterface interface {
+	Create(image *entity.ProductImage) error
+	FindByProduct(productID string) ([]entity.ProductImage, error)
+	FindByID(productID, id string) (*entity.ProductImage, error)
+	Delete(productID, id string) error
+}
+
+type CartInterface interface {
+	FindItems(userID string) ([]entity.CartItem, error)
+	AddItem(userID string, item *entity.CartItem) error
+	SaveItems(userID string, items []entity.CartItem) error
+	RevalidateProduct(productID string) error
+	RemoveItem(userID, productID string) error
+	Clear(userID string) error
 }
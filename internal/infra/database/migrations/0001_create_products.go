@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Databases created before versioned migrations were built by AutoMigrate
+// and already have the products and users tables with this layout, so the
+// first two migrations adopt an existing table instead of failing.
+func init() {
+	type product struct {
+		ID        string `gorm:"type:varchar(36);primaryKey"`
+		Name      string `gorm:"type:varchar(255);not null"`
+		Price     int    `gorm:"not null"`
+		CreatedAt time.Time
+	}
+
+	register(Migration{
+		Version: 1,
+		Name:    "create_products",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(&product{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&product{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("products")
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import "gorm.io/gorm"
+
+func init() {
+	type user struct {
+		ID       string `gorm:"type:varchar(36);primaryKey"`
+		Name     string `gorm:"type:varchar(255);not null"`
+		Email    string `gorm:"type:varchar(255);not null"`
+		Password string `gorm:"type:varchar(255);not null"`
+	}
+
+	register(Migration{
+		Version: 2,
+		Name:    "create_users",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasTable(&user{}) {
+				return nil
+			}
+			return tx.Migrator().CreateTable(&user{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("users")
+		},
+	})
+}
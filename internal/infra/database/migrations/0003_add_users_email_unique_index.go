@@ -0,0 +1,20 @@
+package migrations
+
+import "gorm.io/gorm"
+
+func init() {
+	type user struct {
+		Email string `gorm:"type:varchar(255);uniqueIndex:idx_users_email"`
+	}
+
+	register(Migration{
+		Version: 3,
+		Name:    "add_users_email_unique_index",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateIndex(&user{}, "idx_users_email")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropIndex(&user{}, "idx_users_email")
+		},
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type refreshToken struct {
+		ID        string `gorm:"type:varchar(36);primaryKey"`
+		UserID    string `gorm:"type:varchar(36);not null;index"`
+		FamilyID  string `gorm:"type:varchar(36);not null;index"`
+		TokenHash string `gorm:"type:varchar(64);not null;uniqueIndex"`
+		ExpiresAt time.Time
+		CreatedAt time.Time
+		RotatedAt *time.Time
+		RevokedAt *time.Time
+	}
+
+	register(Migration{
+		Version: 4,
+		Name:    "create_refresh_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&refreshToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("refresh_tokens")
+		},
+	})
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type revokedToken struct {
+		JTI       string    `gorm:"column:jti;type:varchar(36);primaryKey"`
+		ExpiresAt time.Time `gorm:"index"`
+		RevokedAt time.Time
+	}
+
+	register(Migration{
+		Version: 5,
+		Name:    "create_revoked_tokens",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&revokedToken{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("revoked_tokens")
+		},
+	})
+}
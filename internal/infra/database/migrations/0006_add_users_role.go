@@ -0,0 +1,22 @@
+package migrations
+
+import "gorm.io/gorm"
+
+func init() {
+	type user struct {
+		Role string `gorm:"type:varchar(20);not null;default:viewer"`
+	}
+
+	register(Migration{
+		Version: 6,
+		Name:    "add_users_role",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&user{}, "Role")
+		},
+		Down: func(tx *gorm.DB) error {
+			// gorm's sqlite migrator rebuilds the table on DropColumn, which
+			// loses its indexes; plain DROP COLUMN works on every driver.
+			return tx.Exec("ALTER TABLE users DROP COLUMN role").Error
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type product struct {
+		OwnerID *string `gorm:"type:varchar(36)"`
+	}
+	type productWithoutOwner struct {
+		ID        string `gorm:"type:varchar(36);primaryKey"`
+		Name      string `gorm:"type:varchar(255);not null"`
+		Price     int    `gorm:"not null"`
+		CreatedAt time.Time
+	}
+
+	register(Migration{
+		Version: 7,
+		Name:    "add_products_owner",
+		Up: func(tx *gorm.DB) error {
+			// SQLite cannot add a constraint to an existing table, but it
+			// does honor an inline REFERENCES clause on ADD COLUMN.
+			if tx.Dialector.Name() == "sqlite" {
+				err := tx.Exec("ALTER TABLE products ADD COLUMN owner_id varchar(36) REFERENCES users(id) ON DELETE SET NULL").Error
+				if err != nil {
+					return err
+				}
+			} else {
+				err := tx.Migrator().AddColumn(&product{}, "OwnerID")
+				if err != nil {
+					return err
+				}
+				err = tx.Exec("ALTER TABLE products ADD CONSTRAINT fk_products_owner FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL").Error
+				if err != nil {
+					return err
+				}
+			}
+			return tx.Exec("CREATE INDEX idx_products_owner_id ON products (owner_id)").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropIndex(&product{}, "idx_products_owner_id")
+			if err != nil {
+				return err
+			}
+			if tx.Dialector.Name() == "sqlite" {
+				// SQLite refuses DROP COLUMN on a foreign key column, so the
+				// table is rebuilt without it.
+				err = tx.Migrator().RenameTable("products", "products_old")
+				if err != nil {
+					return err
+				}
+				err = tx.Table("products").Migrator().CreateTable(&productWithoutOwner{})
+				if err != nil {
+					return err
+				}
+				err = tx.Exec("INSERT INTO products (id, name, price, created_at) SELECT id, name, price, created_at FROM products_old").Error
+				if err != nil {
+					return err
+				}
+				return tx.Migrator().DropTable("products_old")
+			}
+			err = tx.Migrator().DropConstraint(&product{}, "fk_products_owner")
+			if err != nil {
+				return err
+			}
+			return tx.Exec("ALTER TABLE products DROP COLUMN owner_id").Error
+		},
+	})
+}
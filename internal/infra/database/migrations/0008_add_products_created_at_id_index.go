@@ -0,0 +1,16 @@
+package migrations
+
+import "gorm.io/gorm"
+
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "add_products_created_at_id_index",
+		Up: func(tx *gorm.DB) error {
+			return tx.Exec("CREATE INDEX idx_products_created_at_id ON products (created_at, id)").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropIndex("products", "idx_products_created_at_id")
+		},
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type product struct {
+		DeletedAt *time.Time `gorm:"index:idx_products_deleted_at"`
+	}
+
+	register(Migration{
+		Version: 9,
+		Name:    "add_products_deleted_at",
+		Up: func(tx *gorm.DB) error {
+			err := tx.Migrator().AddColumn(&product{}, "DeletedAt")
+			if err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&product{}, "idx_products_deleted_at")
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Migrator().DropIndex(&product{}, "idx_products_deleted_at")
+			if err != nil {
+				return err
+			}
+			return tx.Exec("ALTER TABLE products DROP COLUMN deleted_at").Error
+		},
+	})
+}
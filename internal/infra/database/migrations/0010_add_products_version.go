@@ -0,0 +1,20 @@
+package migrations
+
+import "gorm.io/gorm"
+
+func init() {
+	type product struct {
+		Version int `gorm:"not null;default:1"`
+	}
+
+	register(Migration{
+		Version: 10,
+		Name:    "add_products_version",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&product{}, "Version")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Exec("ALTER TABLE products DROP COLUMN version").Error
+		},
+	})
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Prices used to be a bare integer of whole units. They become an amount in
+// minor units plus an ISO 4217 currency; existing rows are assumed to be in
+// BRL, which has two decimal digits.
+func init() {
+	type product struct {
+		PriceAmount   int64  `gorm:"not null;default:0"`
+		PriceCurrency string `gorm:"type:varchar(3);not null;default:BRL"`
+	}
+	type legacyProduct struct {
+		Price int `gorm:"not null;default:0"`
+	}
+
+	register(Migration{
+		Version: 11,
+		Name:    "split_products_price",
+		Up: func(tx *gorm.DB) error {
+			for _, column := range []string{"PriceAmount", "PriceCurrency"} {
+				if err := tx.Table("products").Migrator().AddColumn(&product{}, column); err != nil {
+					return err
+				}
+			}
+			err := tx.Exec("UPDATE products SET price_amount = price * 100, price_currency = 'BRL'").Error
+			if err != nil {
+				return err
+			}
+			return tx.Exec("ALTER TABLE products DROP COLUMN price").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			err := tx.Table("products").Migrator().AddColumn(&legacyProduct{}, "Price")
+			if err != nil {
+				return err
+			}
+			err = tx.Exec("UPDATE products SET price = price_amount / 100").Error
+			if err != nil {
+				return err
+			}
+			for _, column := range []string{"price_amount", "price_currency"} {
+				if err := tx.Exec("ALTER TABLE products DROP COLUMN " + column).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
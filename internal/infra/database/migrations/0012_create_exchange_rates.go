@@ -0,0 +1,27 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type exchangeRate struct {
+		Base      string `gorm:"type:varchar(3);primaryKey"`
+		Quote     string `gorm:"type:varchar(3);primaryKey"`
+		Rate      string `gorm:"type:varchar(32);not null"`
+		UpdatedAt time.Time
+	}
+
+	register(Migration{
+		Version: 12,
+		Name:    "create_exchange_rates",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&exchangeRate{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("exchange_rates")
+		},
+	})
+}
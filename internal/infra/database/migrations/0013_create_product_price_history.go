@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type priceChange struct {
+		ID               string    `gorm:"type:varchar(36);primaryKey"`
+		ProductID        string    `gorm:"type:varchar(36);not null;index:idx_product_price_history_product_changed_at,priority:1"`
+		OldPriceAmount   int64     `gorm:"not null"`
+		OldPriceCurrency string    `gorm:"type:varchar(3);not null"`
+		NewPriceAmount   int64     `gorm:"not null"`
+		NewPriceCurrency string    `gorm:"type:varchar(3);not null"`
+		ChangedBy        *string   `gorm:"type:varchar(36)"`
+		ChangedAt        time.Time `gorm:"not null;index:idx_product_price_history_product_changed_at,priority:2"`
+	}
+
+	register(Migration{
+		Version: 13,
+		Name:    "create_product_price_history",
+		Up: func(tx *gorm.DB) error {
+			// No foreign key to products: the history outlives purged products.
+			return tx.Table("product_price_history").Migrator().CreateTable(&priceChange{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("product_price_history")
+		},
+	})
+}
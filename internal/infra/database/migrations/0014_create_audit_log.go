@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type auditEntry struct {
+		ID         string    `gorm:"type:varchar(36);primaryKey"`
+		ActorID    *string   `gorm:"type:varchar(36);index:idx_audit_log_actor_id"`
+		Action     string    `gorm:"type:varchar(32);not null"`
+		EntityType string    `gorm:"type:varchar(32);not null;index:idx_audit_log_entity,priority:1"`
+		EntityID   string    `gorm:"type:varchar(64);not null;index:idx_audit_log_entity,priority:2"`
+		Changes    string    `gorm:"type:text"`
+		RequestID  string    `gorm:"type:varchar(255)"`
+		ClientIP   string    `gorm:"type:varchar(45)"`
+		CreatedAt  time.Time `gorm:"not null;index:idx_audit_log_created_at"`
+	}
+
+	register(Migration{
+		Version: 14,
+		Name:    "create_audit_log",
+		Up: func(tx *gorm.DB) error {
+			return tx.Table("audit_log").Migrator().CreateTable(&auditEntry{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("audit_log")
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type category struct {
+		ID        string  `gorm:"type:varchar(36);primaryKey"`
+		Name      string  `gorm:"type:varchar(255);not null"`
+		ParentID  *string `gorm:"type:varchar(36);index:idx_categories_parent_id"`
+		CreatedAt time.Time
+	}
+	type productCategory struct {
+		ProductID  string `gorm:"type:varchar(36);primaryKey"`
+		CategoryID string `gorm:"type:varchar(36);primaryKey;index:idx_product_categories_category_id"`
+	}
+
+	register(Migration{
+		Version: 15,
+		Name:    "create_categories",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&category{}); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&productCategory{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable("product_categories"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable("categories")
+		},
+	})
+}
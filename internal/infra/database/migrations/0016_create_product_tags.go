@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	type productTag struct {
+		ProductID string `gorm:"type:varchar(36);primaryKey"`
+		Tag       string `gorm:"type:varchar(50);primaryKey;index:idx_product_tags_tag"`
+	}
+
+	register(Migration{
+		Version: 16,
+		Name:    "create_product_tags",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&productTag{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("product_tags")
+		},
+	})
+}
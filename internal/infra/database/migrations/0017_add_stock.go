@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type product struct {
+		Stock int64 `gorm:"not null;default:0"`
+	}
+	type stockAdjustment struct {
+		ID        string    `gorm:"type:varchar(36);primaryKey"`
+		ProductID string    `gorm:"type:varchar(36);not null;index:idx_stock_adjustments_product_created_at,priority:1"`
+		Delta     int64     `gorm:"not null"`
+		Reason    string    `gorm:"type:varchar(32);not null"`
+		Note      string    `gorm:"type:varchar(255)"`
+		ActorID   *string   `gorm:"type:varchar(36)"`
+		CreatedAt time.Time `gorm:"not null;index:idx_stock_adjustments_product_created_at,priority:2"`
+	}
+	type reservation struct {
+		ID        string    `gorm:"type:varchar(36);primaryKey"`
+		ProductID string    `gorm:"type:varchar(36);not null;index:idx_stock_reservations_product_id"`
+		Quantity  int64     `gorm:"not null"`
+		Status    string    `gorm:"type:varchar(16);not null;index:idx_stock_reservations_status_expires_at,priority:1"`
+		CreatedBy *string   `gorm:"type:varchar(36)"`
+		ExpiresAt time.Time `gorm:"not null;index:idx_stock_reservations_status_expires_at,priority:2"`
+		CreatedAt time.Time `gorm:"not null"`
+	}
+
+	register(Migration{
+		Version: 17,
+		Name:    "add_stock",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&product{}, "Stock"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateTable(&stockAdjustment{}); err != nil {
+				return err
+			}
+			return tx.Table("stock_reservations").Migrator().CreateTable(&reservation{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable("stock_reservations"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropTable("stock_adjustments"); err != nil {
+				return err
+			}
+			return tx.Exec("ALTER TABLE products DROP COLUMN stock").Error
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type warehouse struct {
+		ID        string `gorm:"type:varchar(36);primaryKey"`
+		Code      string `gorm:"type:varchar(16);not null;uniqueIndex:idx_warehouses_code"`
+		Name      string `gorm:"type:varchar(255);not null"`
+		CreatedAt time.Time
+	}
+	type warehouseStock struct {
+		ProductID   string `gorm:"type:varchar(36);primaryKey"`
+		WarehouseID string `gorm:"type:varchar(36);primaryKey;index:idx_warehouse_stock_warehouse_id"`
+		Quantity    int64  `gorm:"not null;default:0"`
+	}
+	type stockMovement struct {
+		ID          string    `gorm:"type:varchar(36);primaryKey"`
+		ProductID   string    `gorm:"type:varchar(36);not null;index:idx_stock_movements_product_created_at,priority:1"`
+		WarehouseID string    `gorm:"type:varchar(36);not null"`
+		Delta       int64     `gorm:"not null"`
+		Reason      string    `gorm:"type:varchar(32);not null"`
+		TransferID  *string   `gorm:"type:varchar(36);index:idx_stock_movements_transfer_id"`
+		ActorID     *string   `gorm:"type:varchar(36)"`
+		CreatedAt   time.Time `gorm:"not null;index:idx_stock_movements_product_created_at,priority:2"`
+	}
+	type stockAdjustment struct {
+		WarehouseID *string `gorm:"type:varchar(36)"`
+	}
+
+	register(Migration{
+		Version: 18,
+		Name:    "create_warehouses",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&warehouse{}); err != nil {
+				return err
+			}
+			if err := tx.Table("warehouse_stock").Migrator().CreateTable(&warehouseStock{}); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateTable(&stockMovement{}); err != nil {
+				return err
+			}
+			return tx.Migrator().AddColumn(&stockAdjustment{}, "WarehouseID")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Exec("ALTER TABLE stock_adjustments DROP COLUMN warehouse_id").Error; err != nil {
+				return err
+			}
+			for _, table := range []string{"stock_movements", "warehouse_stock", "warehouses"} {
+				if err := tx.Migrator().DropTable(table); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type productVariant struct {
+		ID            string    `gorm:"type:varchar(36);primaryKey"`
+		ProductID     string    `gorm:"type:varchar(36);not null;index:idx_product_variants_product_id"`
+		SKU           string    `gorm:"column:sku;type:varchar(64);not null;uniqueIndex:idx_product_variants_sku"`
+		Options       string    `gorm:"type:text;not null"`
+		PriceAmount   *int64    `gorm:"column:price_amount"`
+		PriceCurrency *string   `gorm:"column:price_currency;type:varchar(3)"`
+		CreatedAt     time.Time `gorm:"not null"`
+	}
+
+	register(Migration{
+		Version: 19,
+		Name:    "create_product_variants",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&productVariant{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("product_variants")
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type productImage struct {
+		ID           string    `gorm:"type:varchar(36);primaryKey"`
+		ProductID    string    `gorm:"type:varchar(36);not null;index:idx_product_images_product_created_at,priority:1"`
+		ContentType  string    `gorm:"type:varchar(32);not null"`
+		Size         int64     `gorm:"not null"`
+		Width        int       `gorm:"not null"`
+		Height       int       `gorm:"not null"`
+		Key          string    `gorm:"type:varchar(255);not null"`
+		ThumbnailKey string    `gorm:"type:varchar(255);not null"`
+		URL          string    `gorm:"column:url;type:varchar(1024);not null"`
+		ThumbnailURL string    `gorm:"column:thumbnail_url;type:varchar(1024);not null"`
+		CreatedAt    time.Time `gorm:"not null;index:idx_product_images_product_created_at,priority:2"`
+	}
+
+	register(Migration{
+		Version: 20,
+		Name:    "create_product_images",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&productImage{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("product_images")
+		},
+	})
+}
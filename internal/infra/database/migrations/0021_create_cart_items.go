@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	type cartItem struct {
+		UserID                string    `gorm:"type:varchar(36);primaryKey"`
+		ProductID             string    `gorm:"type:varchar(36);primaryKey;index:idx_cart_items_product_id"`
+		Name                  string    `gorm:"type:varchar(255);not null"`
+		Quantity              int64     `gorm:"not null"`
+		UnitPriceAmount       int64     `gorm:"not null"`
+		UnitPriceCurrency     string    `gorm:"type:varchar(3);not null"`
+		PreviousPriceAmount   *int64    `gorm:"column:previous_price_amount"`
+		PreviousPriceCurrency *string   `gorm:"type:varchar(3)"`
+		Status                string    `gorm:"type:varchar(16);not null"`
+		AddedAt               time.Time `gorm:"not null"`
+	}
+
+	register(Migration{
+		Version: 21,
+		Name:    "create_cart_items",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&cartItem{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("cart_items")
+		},
+	})
+}
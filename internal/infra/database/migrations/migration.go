@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+var registered []Migration
+
+// register adds a migration to the set applied by NewMigrator. Each migration
+// file calls it from init, so the version must be unique across the package.
+func register(m Migration) {
+	for _, r := range registered {
+		if r.Version == m.Version {
+			panic(fmt.Sprintf("migrations: duplicate version %d (%s, %s)", m.Version, r.Name, m.Name))
+		}
+	}
+	registered = append(registered, m)
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Version < registered[j].Version
+	})
+}
+
+func All() []Migration {
+	all := make([]Migration, len(registered))
+	copy(all, registered)
+	return all
+}
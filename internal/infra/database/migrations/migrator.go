@@ -0,0 +1,180 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSchemaBehind   = errors.New("database schema is behind the application")
+	ErrUnknownVersion = errors.New("unknown migration version")
+	ErrNothingToUndo  = errors.New("no migration to roll back")
+)
+
+type SchemaMigration struct {
+	Version   uint   `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type Status struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	DB         *gorm.DB
+	Migrations []Migration
+}
+
+func NewMigrator(db *gorm.DB) *Migrator {
+	return &Migrator{DB: db, Migrations: All()}
+}
+
+func (m *Migrator) Latest() uint {
+	if len(m.Migrations) == 0 {
+		return 0
+	}
+	return m.Migrations[len(m.Migrations)-1].Version
+}
+
+func (m *Migrator) Version() (uint, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	var version uint
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+func (m *Migrator) Up() error {
+	return m.To(m.Latest())
+}
+
+func (m *Migrator) Down() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	for i := len(m.Migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[m.Migrations[i].Version]; ok {
+			return m.rollback(m.Migrations[i])
+		}
+	}
+	return ErrNothingToUndo
+}
+
+// To migrates up or down until exactly the migrations with a version lower
+// than or equal to version are applied. Version 0 rolls everything back.
+func (m *Migrator) To(version uint) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	for i := len(m.Migrations) - 1; i >= 0; i-- {
+		mig := m.Migrations[i]
+		if _, ok := applied[mig.Version]; ok && mig.Version > version {
+			if err := m.rollback(mig); err != nil {
+				return err
+			}
+		}
+	}
+	for _, mig := range m.Migrations {
+		if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+			if err := m.apply(mig); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.Migrations))
+	for _, mig := range m.Migrations {
+		status := Status{Version: mig.Version, Name: mig.Name}
+		if row, ok := applied[mig.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &row.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Check returns ErrSchemaBehind when any known migration is still pending.
+func (m *Migrator) Check() error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if !status.Applied {
+			return fmt.Errorf("%w: migration %d (%s) is pending", ErrSchemaBehind, status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(mig Migration) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := mig.Up(tx); err != nil {
+			return fmt.Errorf("migration %d (%s) up: %w", mig.Version, mig.Name, err)
+		}
+		return tx.Create(&SchemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error
+	})
+}
+
+func (m *Migrator) rollback(mig Migration) error {
+	return m.DB.Transaction(func(tx *gorm.DB) error {
+		if err := mig.Down(tx); err != nil {
+			return fmt.Errorf("migration %d (%s) down: %w", mig.Version, mig.Name, err)
+		}
+		return tx.Where("version = ?", mig.Version).Delete(&SchemaMigration{}).Error
+	})
+}
+
+func (m *Migrator) applied() (map[uint]SchemaMigration, error) {
+	if err := m.DB.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+	var rows []SchemaMigration
+	if err := m.DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[uint]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func (m *Migrator) known(version uint) bool {
+	for _, mig := range m.Migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
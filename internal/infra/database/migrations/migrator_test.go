@@ -0,0 +1,136 @@
+package migrations
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestMigrator(t *testing.T) *Migrator {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	return NewMigrator(db)
+}
+
+func TestMigrator_Up(t *testing.T) {
+	m := newTestMigrator(t)
+
+	err := m.Check()
+	assert.True(t, errors.Is(err, ErrSchemaBehind))
+
+	err = m.Up()
+	assert.Nil(t, err)
+	assert.Nil(t, m.Check())
+
+	version, err := m.Version()
+	assert.Nil(t, err)
+	assert.Equal(t, m.Latest(), version)
+	assert.True(t, m.DB.Migrator().HasTable("products"))
+	assert.True(t, m.DB.Migrator().HasTable("users"))
+	assert.True(t, m.DB.Migrator().HasIndex("users", "idx_users_email"))
+
+	err = m.Up()
+	assert.Nil(t, err)
+}
+
+func TestMigrator_Down(t *testing.T) {
+	m := newTestMigrator(t)
+	assert.Nil(t, m.Up())
+
+	err := m.Down()
+	assert.Nil(t, err)
+	version, err := m.Version()
+	assert.Nil(t, err)
+	assert.Equal(t, m.Latest()-1, version)
+	assert.True(t, errors.Is(m.Check(), ErrSchemaBehind))
+}
+
+func TestMigrator_To(t *testing.T) {
+	m := newTestMigrator(t)
+
+	err := m.To(1)
+	assert.Nil(t, err)
+	assert.True(t, m.DB.Migrator().HasTable("products"))
+	assert.False(t, m.DB.Migrator().HasTable("users"))
+
+	err = m.To(m.Latest())
+	assert.Nil(t, err)
+	assert.True(t, m.DB.Migrator().HasTable("users"))
+
+	err = m.To(0)
+	assert.Nil(t, err)
+	assert.False(t, m.DB.Migrator().HasTable("products"))
+	assert.True(t, errors.Is(m.Down(), ErrNothingToUndo))
+
+	err = m.To(9999)
+	assert.True(t, errors.Is(err, ErrUnknownVersion))
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m := newTestMigrator(t)
+	assert.Nil(t, m.To(1))
+
+	statuses, err := m.Status()
+	assert.Nil(t, err)
+	assert.Len(t, statuses, len(m.Migrations))
+	assert.True(t, statuses[0].Applied)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.False(t, statuses[1].Applied)
+	assert.Nil(t, statuses[1].AppliedAt)
+}
+
+func TestMigrator_ToKeepsRows(t *testing.T) {
+	m := newTestMigrator(t)
+	assert.Nil(t, m.To(10))
+	m.DB.Exec("INSERT INTO products (id, name, price, created_at) VALUES ('d069eb23-c7a5-4c87-ae70-455f687771e8', 'Product 1', 10, CURRENT_TIMESTAMP)")
+
+	assert.Nil(t, m.Up())
+	var amount int64
+	m.DB.Table("products").Select("price_amount").Scan(&amount)
+	assert.Equal(t, int64(1000), amount)
+
+	err := m.To(6)
+	assert.Nil(t, err)
+	assert.False(t, m.DB.Migrator().HasColumn("products", "owner_id"))
+	assert.False(t, m.DB.Migrator().HasColumn("products", "price_amount"))
+
+	var price int
+	m.DB.Table("products").Select("price").Scan(&price)
+	assert.Equal(t, 10, price)
+	assert.Nil(t, m.Up())
+}
+
+func TestMigrator_UpFromAutoMigrate(t *testing.T) {
+	type Product struct {
+		ID        pkgEntity.ID
+		Name      string
+		Price     int
+		CreatedAt time.Time
+	}
+	type User struct {
+		ID       pkgEntity.ID
+		Name     string
+		Email    string
+		Password string
+	}
+	m := newTestMigrator(t)
+	assert.Nil(t, m.DB.AutoMigrate(&Product{}, &User{}))
+	assert.Nil(t, m.DB.Create(&Product{ID: pkgEntity.NewID(), Name: "Product 1", Price: 10, CreatedAt: time.Now()}).Error)
+	assert.Nil(t, m.DB.Create(&User{ID: pkgEntity.NewID(), Name: "John", Email: "j@j.com", Password: "hash"}).Error)
+
+	assert.Nil(t, m.Up())
+	assert.Nil(t, m.Check())
+	var amount int64
+	m.DB.Table("products").Select("price_amount").Scan(&amount)
+	assert.Equal(t, int64(1000), amount)
+	var users int64
+	m.DB.Table("users").Count(&users)
+	assert.Equal(t, int64(1), users)
+}
@@ -1,35 +1,104 @@
 package database
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
 	"gorm.io/gorm"
 )
 
+// errNoRowsUpdated rolls back an update whose version check failed.
+var errNoRowsUpdated = errors.New("no rows updated")
+
 type Product struct {
 	DB *gorm.DB
 }
 
+// Create saves the product along with its tags.
 func (p *Product) Create(product *entity.Product) error {
-	return p.DB.Create(product).Error
+	return p.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		links := make([]productTag, len(product.Tags))
+		for i, tag := range product.Tags {
+			links[i] = productTag{ProductID: product.ID.String(), Tag: tag}
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.Create(&links).Error
+	})
 }
 
-func (p *Product) FindAll(page, limit int, sort string) ([]entity.Product, error) {
-	if sort != "" && sort != "asc" && sort != "desc" {
-		sort = "asc"
+// FindAll returns the requested page and the total number of products
+// matching the query's filters.
+func (p *Product) FindAll(query ProductQuery) ([]entity.Product, int64, error) {
+	query = query.Normalize()
+
+	total, err := p.Count(query)
+	if err != nil {
+		return nil, 0, err
 	}
-	if page < 1 {
-		page = 1
+
+	var products []entity.Product
+	db := query.order(query.filter(p.DB))
+	err = db.Offset((query.Page - 1) * query.Limit).Limit(query.Limit).Find(&products).Error
+	if err != nil {
+		return nil, 0, err
 	}
-	if limit < 10 {
-		limit = 10
+	if err := p.loadDetails(products); err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+// FindAfter returns up to query.Limit products ordered by (created_at, id)
+// that come after the after cursor, or from the start when it is nil. Page
+// and SortBy are ignored. The returned cursor points at the last product and
+// is nil when there is nothing left to read.
+func (p *Product) FindAfter(query ProductQuery, after *pkgEntity.Cursor) ([]entity.Product, *pkgEntity.Cursor, error) {
+	query.SortBy = "created_at"
+	query = query.Normalize()
+
+	if after != nil && (after.SortDir != query.SortDir || after.Filter != query.FilterHash()) {
+		return nil, nil, pkgEntity.ErrCursorMismatch
+	}
+	db := query.filter(p.DB)
+	if after != nil {
+		op := ">"
+		if query.SortDir == "desc" {
+			op = "<"
+		}
+		db = db.Where(
+			"(created_at "+op+" ? OR (created_at = ? AND id "+op+" ?))",
+			after.CreatedAt, after.CreatedAt, after.ID,
+		)
 	}
 
 	var products []entity.Product
-	err := p.DB.Order("created_at " + sort).Offset((page - 1) * limit).Limit(limit).Find(&products).Error
+	err := query.order(db).Limit(query.Limit + 1).Find(&products).Error
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if len(products) <= query.Limit {
+		return products, nil, p.loadDetails(products)
+	}
+	products = products[:query.Limit]
+	if err := p.loadDetails(products); err != nil {
+		return nil, nil, err
 	}
-	return products, nil
+	next := query.CursorAfter(products[len(products)-1])
+	return products, &next, nil
+}
+
+func (p *Product) Count(query ProductQuery) (int64, error) {
+	var total int64
+	err := query.filter(p.DB.Model(&entity.Product{})).Count(&total).Error
+	return total, err
 }
 
 func (p *Product) FindByID(id string) (*entity.Product, error) {
@@ -38,23 +107,228 @@ func (p *Product) FindByID(id string) (*entity.Product, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &product, nil
+	return p.withDetails(product)
 }
 
-func (p *Product) Update(product *entity.Product) error {
-	_, err := p.FindByID(product.ID.String())
+// FindByIDs returns the products with the given ids that are not in the
+// trash, in no particular order and without their tags and images.
+func (p *Product) FindByIDs(ids []string) ([]entity.Product, error) {
+	var products []entity.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := p.DB.Where("id IN ?", ids).Find(&products).Error
+	return products, err
+}
+
+// Update saves product only if its Version still matches the stored row,
+// bumping the version in the same statement. On success product.Version
+// holds the new version. A price change is recorded in the price history,
+// attributed to actorID, within the same transaction.
+func (p *Product) Update(product *entity.Product, actorID string) error {
+	return p.UpdateColumns(product, actorID, "name", "price", "owner_id")
+}
+
+// UpdateColumns is Update restricted to the given columns. "price" covers
+// both the amount and the currency.
+func (p *Product) UpdateColumns(product *entity.Product, actorID string, columns ...string) error {
+	values := map[string]map[string]interface{}{
+		"name":     {"name": product.Name},
+		"price":    {"price_amount": product.Price.Amount, "price_currency": product.Price.Currency},
+		"owner_id": {"owner_id": product.OwnerID},
+	}
+	changes := map[string]interface{}{"version": gorm.Expr("version + 1")}
+	priceChanged := false
+	for _, column := range columns {
+		value, ok := values[column]
+		if !ok {
+			return fmt.Errorf("column %q cannot be updated", column)
+		}
+		for name, v := range value {
+			changes[name] = v
+		}
+		priceChanged = priceChanged || column == "price"
+	}
+
+	err := p.DB.Transaction(func(tx *gorm.DB) error {
+		var current entity.Product
+		if priceChanged {
+			err := tx.Where("id = ? AND version = ?", product.ID, product.Version).First(&current).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errNoRowsUpdated
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&entity.Product{}).
+			Where("id = ? AND version = ?", product.ID, product.Version).
+			Updates(changes)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errNoRowsUpdated
+		}
+
+		if priceChanged && current.Price != product.Price {
+			var changedBy *pkgEntity.ID
+			if id, err := pkgEntity.ParseID(actorID); err == nil {
+				changedBy = &id
+			}
+			return tx.Create(entity.NewPriceChange(product.ID, current.Price, product.Price, changedBy)).Error
+		}
+		return nil
+	})
+	if err == errNoRowsUpdated {
+		return p.conflictOrNotFound(product.ID.String())
+	}
 	if err != nil {
 		return err
 	}
-	return p.DB.Save(product).Error
+	product.Version++
+	return nil
+}
+
+// FindPriceHistory lists the price changes of a product, oldest first,
+// optionally limited to changes made at or after from and before to.
+func (p *Product) FindPriceHistory(id string, from, to *time.Time) ([]entity.PriceChange, error) {
+	db := p.DB.Where("product_id = ?", id)
+	if from != nil {
+		db = db.Where("changed_at >= ?", *from)
+	}
+	if to != nil {
+		db = db.Where("changed_at < ?", *to)
+	}
+	var changes []entity.PriceChange
+	err := db.Order("changed_at").Order("id").Find(&changes).Error
+	return changes, err
+}
+
+// Delete moves the product to the trash; it stays hidden from every other
+// query until restored.
+func (p *Product) Delete(id string, version int) error {
+	result := p.DB.Where("id = ? AND version = ?", id, version).Delete(&entity.Product{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return p.conflictOrNotFound(id)
+	}
+	return nil
 }
 
-func (p *Product) Delete(id string) error {
+func (p *Product) conflictOrNotFound(id string) error {
 	_, err := p.FindByID(id)
 	if err != nil {
 		return err
 	}
-	return p.DB.Where("id = ?", id).Delete(&entity.Product{}).Error
+	return entity.ErrVersionConflict
+}
+
+func (p *Product) FindDeleted(query ProductQuery) ([]entity.Product, int64, error) {
+	query = query.Normalize()
+	trash := func() *gorm.DB {
+		return query.filter(p.DB.Unscoped().Model(&entity.Product{}).Where("deleted_at IS NOT NULL"))
+	}
+
+	var total int64
+	err := trash().Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var products []entity.Product
+	err = query.order(trash()).Offset((query.Page - 1) * query.Limit).Limit(query.Limit).Find(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := p.loadDetails(products); err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+func (p *Product) FindDeletedByID(id string) (*entity.Product, error) {
+	var product entity.Product
+	err := p.DB.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return p.withDetails(product)
+}
+
+// FindAnyByID finds the product whether it is in the trash or not.
+func (p *Product) FindAnyByID(id string) (*entity.Product, error) {
+	var product entity.Product
+	err := p.DB.Unscoped().Where("id = ?", id).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return p.withDetails(product)
+}
+
+// loadDetails fills the tags and images of the products, which are kept in
+// tables of their own.
+func (p *Product) loadDetails(products []entity.Product) error {
+	if err := p.loadTags(products); err != nil {
+		return err
+	}
+	return p.loadImages(products)
+}
+
+func (p *Product) withDetails(product entity.Product) (*entity.Product, error) {
+	products := []entity.Product{product}
+	if err := p.loadDetails(products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+func (p *Product) Restore(id string) error {
+	result := p.DB.Unscoped().Model(&entity.Product{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{"deleted_at": nil, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purge removes the product row, its tags, category links, variants, image
+// records, cart items and warehouse quantities for good, whether it is in the trash or
+// not. Stock history is kept; deleting the image files is left to the
+// caller, after the transaction commits.
+func (p *Product) Purge(id string) error {
+	return p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("id = ?", id).Delete(&entity.Product{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&productTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&entity.WarehouseStock{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&productVariant{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&entity.ProductImage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&cartItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("product_id = ?", id).Delete(&productCategory{}).Error
+	})
 }
 
 func NewProduct(db *gorm.DB) *Product {
@@ -3,104 +3,290 @@ package database
 import (
 	"fmt"
 	"math/rand"
+	"net/url"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database/migrations"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-func TestCreateProduct(t *testing.T) {
-	// Arrange
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+// newProductTestDB opens a private in-memory database with the real schema,
+// which covers every table that product queries join. The database is named
+// and cached so that every pooled connection sees the same one.
+func newProductTestDB(t *testing.T) *gorm.DB {
+	return openProductTestDB(t, "file:"+url.PathEscape(t.Name())+"?mode=memory&cache=shared")
+}
+
+func openProductTestDB(t *testing.T, dsn string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatal(err)
 	}
-	db.AutoMigrate(&entity.Product{})
-	product, _ := entity.NewProduct("Product 1", 10)
+	return db
+}
+
+func brl(amount int64) pkgEntity.Money {
+	return pkgEntity.Money{Amount: amount, Currency: "BRL"}
+}
+
+func TestCreateProduct(t *testing.T) {
+	// Arrange
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(10))
 	productDB := NewProduct(db)
 
 	// Act
-	err = productDB.Create(product)
+	err := productDB.Create(product)
 
 	// Assert
 	assert.Nil(t, err)
 	assert.NotNil(t, product.ID)
 	assert.NotEmpty(t, product.ID)
 	assert.Equal(t, "Product 1", product.Name)
-	assert.Equal(t, 10, product.Price)
+	assert.Equal(t, brl(10), product.Price)
 	assert.NotEmpty(t, product.CreatedAt)
 }
 
 func TestProduct_FindAll(t *testing.T) {
 	// Arrange
-	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
-	if err != nil {
-		t.Error(err)
-	}
-
-	db.AutoMigrate(&entity.Product{})
+	db := newProductTestDB(t)
 
 	for i := 1; i < 28; i++ {
-		product, err := entity.NewProduct(fmt.Sprintf("Product %d", i), rand.Intn(100)+1)
+		product, err := entity.NewProduct(fmt.Sprintf("Product %d", i), brl(int64(rand.Intn(100)+1)))
 		assert.Nil(t, err)
 		db.Create(product)
 	}
 
 	productDB := NewProduct(db)
 
-	products, err := productDB.FindAll(1, 10, "asc")
+	products, total, err := productDB.FindAll(ProductQuery{Page: 1, Limit: 10, SortDir: "asc"})
 	assert.Nil(t, err)
+	assert.Equal(t, int64(27), total)
 	assert.Len(t, products, 10)
 	assert.Equal(t, "Product 1", products[0].Name)
 	assert.Equal(t, "Product 10", products[9].Name)
 
-	products, err = productDB.FindAll(2, 10, "asc")
+	products, _, err = productDB.FindAll(ProductQuery{Page: 2, Limit: 10, SortDir: "asc"})
 	assert.Nil(t, err)
 	assert.Len(t, products, 10)
 	assert.Equal(t, "Product 11", products[0].Name)
 	assert.Equal(t, "Product 20", products[9].Name)
 
-	products, err = productDB.FindAll(3, 10, "asc")
+	products, _, err = productDB.FindAll(ProductQuery{Page: 3, Limit: 10, SortDir: "asc"})
 	assert.Nil(t, err)
 	assert.Len(t, products, 7)
 	assert.Equal(t, "Product 21", products[0].Name)
 	assert.Equal(t, "Product 27", products[6].Name)
 
-	products, err = productDB.FindAll(3, 10, "")
+	products, _, err = productDB.FindAll(ProductQuery{Page: 3, Limit: 10})
 	assert.Nil(t, err)
 	assert.Len(t, products, 7)
 	assert.Equal(t, "Product 21", products[0].Name)
 	assert.Equal(t, "Product 27", products[6].Name)
 
-	products, err = productDB.FindAll(3, 9, "asc")
+	products, _, err = productDB.FindAll(ProductQuery{Page: 3, Limit: 9, SortDir: "asc"})
 	assert.Nil(t, err)
 	assert.Len(t, products, 7)
 	assert.Equal(t, "Product 21", products[0].Name)
 	assert.Equal(t, "Product 27", products[6].Name)
 
-	products, err = productDB.FindAll(3, 10, "desc")
+	products, _, err = productDB.FindAll(ProductQuery{Page: 3, Limit: 10, SortDir: "desc"})
 	assert.Nil(t, err)
 	assert.Len(t, products, 7)
 	assert.Equal(t, "Product 7", products[0].Name)
 	assert.Equal(t, "Product 1", products[6].Name)
 
-	products, err = productDB.FindAll(3, 12, "desc")
+	products, _, err = productDB.FindAll(ProductQuery{Page: 3, Limit: 12, SortDir: "desc"})
 	assert.Nil(t, err)
 	assert.Len(t, products, 3)
 	assert.Equal(t, "Product 3", products[0].Name)
 	assert.Equal(t, "Product 1", products[2].Name)
 }
 
+func TestProduct_FindAll_ByOwner(t *testing.T) {
+	db := newProductTestDB(t)
+
+	ownerID := pkgEntity.NewID()
+	for i := 1; i <= 4; i++ {
+		product, _ := entity.NewProduct(fmt.Sprintf("Product %d", i), brl(10))
+		if i%2 == 0 {
+			product.OwnerID = &ownerID
+		}
+		db.Create(product)
+	}
+	productDB := NewProduct(db)
+
+	products, _, err := productDB.FindAll(ProductQuery{OwnerID: ownerID.String()})
+	assert.Nil(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, "Product 2", products[0].Name)
+	assert.Equal(t, "Product 4", products[1].Name)
+	assert.True(t, products[0].IsOwnedBy(ownerID.String()))
+}
+
+func TestProduct_FindAll_Filters(t *testing.T) {
+	db := newProductTestDB(t)
+
+	base := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)
+	for i, name := range []string{"Blue Shirt", "Red Shirt", "Blue Jeans", "100% Cotton Socks", "Sneakers"} {
+		product, _ := entity.NewProduct(name, brl(int64(i+1)*100))
+		product.CreatedAt = base.AddDate(0, 0, i)
+		db.Create(product)
+	}
+	productDB := NewProduct(db)
+
+	products, total, err := productDB.FindAll(ProductQuery{Search: "shirt"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, products, 2)
+
+	products, _, err = productDB.FindAll(ProductQuery{Search: "0%"})
+	assert.Nil(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, "100% Cotton Socks", products[0].Name)
+
+	products, _, err = productDB.FindAll(ProductQuery{Search: "_"})
+	assert.Nil(t, err)
+	assert.Len(t, products, 0)
+
+	minPrice, maxPrice := int64(200), int64(400)
+	products, _, err = productDB.FindAll(ProductQuery{MinPrice: &minPrice, MaxPrice: &maxPrice, PriceCurrency: "BRL"})
+	assert.Nil(t, err)
+	assert.Len(t, products, 3)
+	products, _, err = productDB.FindAll(ProductQuery{MinPrice: &minPrice, MaxPrice: &maxPrice, PriceCurrency: "USD"})
+	assert.Nil(t, err)
+	assert.Len(t, products, 0)
+
+	after, before := base.AddDate(0, 0, 1), base.AddDate(0, 0, 3)
+	products, _, err = productDB.FindAll(ProductQuery{CreatedAfter: &after, CreatedBefore: &before})
+	assert.Nil(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, "Red Shirt", products[0].Name)
+	assert.Equal(t, "Blue Jeans", products[1].Name)
+
+	products, _, err = productDB.FindAll(ProductQuery{SortBy: "name"})
+	assert.Nil(t, err)
+	assert.Equal(t, "100% Cotton Socks", products[0].Name)
+	assert.Equal(t, "Sneakers", products[4].Name)
+
+	products, _, err = productDB.FindAll(ProductQuery{SortBy: "price", SortDir: "desc", PriceCurrency: "BRL"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Sneakers", products[0].Name)
+	assert.Equal(t, "Blue Shirt", products[4].Name)
+
+	products, _, err = productDB.FindAll(ProductQuery{SortBy: "price; DROP TABLE products", SortDir: "desc"})
+	assert.Nil(t, err)
+	assert.Len(t, products, 5)
+	assert.Equal(t, "Sneakers", products[0].Name)
+}
+
+func TestProduct_FindAfter(t *testing.T) {
+	db := openProductTestDB(t, filepath.Join(t.TempDir(), "test.db"))
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+
+	base := time.Now().Add(-time.Hour)
+	seeded := map[pkgEntity.ID]bool{}
+	for i := 0; i < 25; i++ {
+		product, _ := entity.NewProduct(fmt.Sprintf("Product %d", i), brl(10))
+		// every other pair of products shares a timestamp to exercise the id tiebreaker
+		product.CreatedAt = base.Add(time.Duration(i/2) * time.Second)
+		db.Create(product)
+		seeded[product.ID] = false
+	}
+	productDB := NewProduct(db)
+
+	// Keep inserting while the client pages through the catalog, both before
+	// the current position (which would shift OFFSET pages) and after it.
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			product, _ := entity.NewProduct(fmt.Sprintf("Concurrent %d", i), brl(10))
+			if i%2 == 0 {
+				product.CreatedAt = base.Add(-time.Minute)
+			}
+			db.Create(product)
+		}
+	}()
+
+	lastSeeded := base.Add(12 * time.Second)
+	seen := map[pkgEntity.ID]int{}
+	var cursor *pkgEntity.Cursor
+	for {
+		products, next, err := productDB.FindAfter(ProductQuery{Limit: 10}, cursor)
+		assert.Nil(t, err)
+		for _, product := range products {
+			seen[product.ID]++
+		}
+		if next == nil || next.CreatedAt.After(lastSeeded) {
+			break
+		}
+		cursor = next
+	}
+	close(stop)
+	<-done
+
+	for id := range seeded {
+		assert.Equal(t, 1, seen[id], "seeded product %s", id)
+	}
+	for id, count := range seen {
+		assert.Equal(t, 1, count, "product %s returned more than once", id)
+	}
+}
+
+func TestProduct_FindAfter_Desc(t *testing.T) {
+	db := newProductTestDB(t)
+
+	for i := 1; i <= 12; i++ {
+		product, _ := entity.NewProduct(fmt.Sprintf("Product %d", i), brl(10))
+		db.Create(product)
+	}
+	productDB := NewProduct(db)
+
+	products, next, err := productDB.FindAfter(ProductQuery{Limit: 10, SortDir: "desc"}, nil)
+	assert.Nil(t, err)
+	assert.Len(t, products, 10)
+	assert.Equal(t, "Product 12", products[0].Name)
+	assert.NotNil(t, next)
+
+	_, _, err = productDB.FindAfter(ProductQuery{Limit: 10}, next)
+	assert.Equal(t, pkgEntity.ErrCursorMismatch, err)
+	_, _, err = productDB.FindAfter(ProductQuery{Limit: 10, SortDir: "desc", Search: "Product 1"}, next)
+	assert.Equal(t, pkgEntity.ErrCursorMismatch, err)
+
+	products, next, err = productDB.FindAfter(ProductQuery{Limit: 10, SortDir: "desc"}, next)
+	assert.Nil(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, "Product 2", products[0].Name)
+	assert.Equal(t, "Product 1", products[1].Name)
+	assert.Nil(t, next)
+}
+
 func TestProduct_FindByID(t *testing.T) {
 	// Arrange
-	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
-	if err != nil {
-		t.Error(err)
-	}
-	db.AutoMigrate(&entity.Product{})
-	product, _ := entity.NewProduct("Product 1", 10)
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(10))
 	productDB := NewProduct(db)
 	productDB.Create(product)
 
@@ -111,46 +297,218 @@ func TestProduct_FindByID(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, productFound)
 	assert.Equal(t, "Product 1", productFound.Name)
-	assert.Equal(t, 10, productFound.Price)
+	assert.Equal(t, brl(10), productFound.Price)
 	assert.NotEmpty(t, productFound.CreatedAt)
 }
 
+func TestProduct_FindByIDs(t *testing.T) {
+	db := newProductTestDB(t)
+	productDB := NewProduct(db)
+
+	shirt, _ := entity.NewProduct("Shirt", brl(10))
+	mug, _ := entity.NewProduct("Mug", brl(10))
+	trashed, _ := entity.NewProduct("Trashed", brl(10))
+	for _, product := range []*entity.Product{shirt, mug, trashed} {
+		productDB.Create(product)
+	}
+	productDB.Delete(trashed.ID.String(), trashed.Version)
+
+	products, err := productDB.FindByIDs([]string{shirt.ID.String(), trashed.ID.String(), pkgEntity.NewID().String()})
+	assert.Nil(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, shirt.ID, products[0].ID)
+
+	products, err = productDB.FindByIDs(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, products)
+}
+
 func TestProduct_Update(t *testing.T) {
 	// Arrange
-	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
-	if err != nil {
-		t.Error(err)
-	}
-	db.AutoMigrate(&entity.Product{})
-	product, _ := entity.NewProduct("Product 1", 10)
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(10))
 	productDB := NewProduct(db)
 	productDB.Create(product)
 
 	// Act
 	product.Name = "Product 2"
-	product.Price = 20
-	err = productDB.Update(product)
+	product.Price = brl(20)
+	err := productDB.Update(product, "")
 
 	// Assert
 	assert.Nil(t, err)
 	assert.Equal(t, "Product 2", product.Name)
-	assert.Equal(t, 20, product.Price)
+	assert.Equal(t, brl(20), product.Price)
+	assert.Equal(t, 2, product.Version)
+
+	productFound, _ := productDB.FindByID(product.ID.String())
+	assert.Equal(t, "Product 2", productFound.Name)
+	assert.Equal(t, 2, productFound.Version)
+}
+
+func TestProduct_UpdateColumns(t *testing.T) {
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(10))
+	productDB := NewProduct(db)
+	productDB.Create(product)
+
+	product.Name = "Product 2"
+	product.Price = brl(20)
+	err := productDB.UpdateColumns(product, "", "price")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, product.Version)
+
+	productFound, _ := productDB.FindByID(product.ID.String())
+	assert.Equal(t, "Product 1", productFound.Name)
+	assert.Equal(t, brl(20), productFound.Price)
+
+	err = productDB.UpdateColumns(product, "", "created_at")
+	assert.NotNil(t, err)
+}
+
+func TestProduct_Update_VersionConflict(t *testing.T) {
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(10))
+	productDB := NewProduct(db)
+	productDB.Create(product)
+
+	first, _ := productDB.FindByID(product.ID.String())
+	second, _ := productDB.FindByID(product.ID.String())
+
+	first.Price = brl(20)
+	err := productDB.Update(first, "")
+	assert.Nil(t, err)
+
+	second.Price = brl(30)
+	err = productDB.Update(second, "")
+	assert.Equal(t, entity.ErrVersionConflict, err)
+
+	err = productDB.Delete(product.ID.String(), 1)
+	assert.Equal(t, entity.ErrVersionConflict, err)
+
+	productFound, _ := productDB.FindByID(product.ID.String())
+	assert.Equal(t, brl(20), productFound.Price)
+	assert.Equal(t, 2, productFound.Version)
+
+	missing, _ := entity.NewProduct("Product 2", brl(10))
+	err = productDB.Update(missing, "")
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
 }
 
 func TestProduct_Delete(t *testing.T) {
 	// Arrange
-	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
-	if err != nil {
-		t.Error(err)
-	}
-	db.AutoMigrate(&entity.Product{})
-	product, _ := entity.NewProduct("Product 1", 10)
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(10))
 	productDB := NewProduct(db)
 	productDB.Create(product)
 
 	// Act
-	err = productDB.Delete(product.ID.String())
+	err := productDB.Delete(product.ID.String(), product.Version)
 
 	// Assert
 	assert.Nil(t, err)
+	_, err = productDB.FindByID(product.ID.String())
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	products, total, _ := productDB.FindAll(ProductQuery{})
+	assert.Len(t, products, 0)
+	assert.Equal(t, int64(0), total)
+
+	deleted, err := productDB.FindDeletedByID(product.ID.String())
+	assert.Nil(t, err)
+	assert.True(t, deleted.IsDeleted())
+}
+
+func TestProduct_FindDeleted(t *testing.T) {
+	db := newProductTestDB(t)
+	productDB := NewProduct(db)
+	for i := 1; i <= 3; i++ {
+		product, _ := entity.NewProduct(fmt.Sprintf("Product %d", i), brl(10))
+		productDB.Create(product)
+		if i != 2 {
+			productDB.Delete(product.ID.String(), product.Version)
+		}
+	}
+
+	products, total, err := productDB.FindDeleted(ProductQuery{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "Product 1", products[0].Name)
+	assert.Equal(t, "Product 3", products[1].Name)
+}
+
+func TestProduct_Restore(t *testing.T) {
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(10))
+	productDB := NewProduct(db)
+	productDB.Create(product)
+
+	err := productDB.Restore(product.ID.String())
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+
+	productDB.Delete(product.ID.String(), product.Version)
+	err = productDB.Restore(product.ID.String())
+	assert.Nil(t, err)
+
+	productFound, err := productDB.FindByID(product.ID.String())
+	assert.Nil(t, err)
+	assert.False(t, productFound.IsDeleted())
+}
+
+func TestProduct_Purge(t *testing.T) {
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(10))
+	productDB := NewProduct(db)
+	productDB.Create(product)
+	productDB.Delete(product.ID.String(), product.Version)
+
+	found, err := productDB.FindAnyByID(product.ID.String())
+	assert.Nil(t, err)
+	assert.True(t, found.IsDeleted())
+
+	err = productDB.Purge(product.ID.String())
+	assert.Nil(t, err)
+
+	var count int64
+	db.Unscoped().Model(&entity.Product{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+	assert.Equal(t, gorm.ErrRecordNotFound, productDB.Purge(product.ID.String()))
+}
+
+func TestProduct_FindPriceHistory(t *testing.T) {
+	db := newProductTestDB(t)
+	product, _ := entity.NewProduct("Product 1", brl(1000))
+	productDB := NewProduct(db)
+	productDB.Create(product)
+	actorID := pkgEntity.NewID()
+
+	product.Name = "Product 2"
+	assert.Nil(t, productDB.Update(product, actorID.String()))
+	start := time.Now()
+	product.Price = brl(1500)
+	assert.Nil(t, productDB.Update(product, actorID.String()))
+	product.Price = brl(1200)
+	assert.Nil(t, productDB.UpdateColumns(product, "", "price"))
+
+	stale := *product
+	stale.Version = 1
+	stale.Price = brl(9900)
+	assert.Equal(t, entity.ErrVersionConflict, productDB.Update(&stale, actorID.String()))
+
+	changes, err := productDB.FindPriceHistory(product.ID.String(), nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, brl(1000), changes[0].OldPrice)
+	assert.Equal(t, brl(1500), changes[0].NewPrice)
+	assert.Equal(t, &actorID, changes[0].ChangedBy)
+	assert.False(t, changes[0].ChangedAt.Before(start))
+	assert.Equal(t, brl(1200), changes[1].NewPrice)
+	assert.Nil(t, changes[1].ChangedBy)
+
+	future := time.Now().Add(time.Hour)
+	changes, err = productDB.FindPriceHistory(product.ID.String(), &future, nil)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 0)
+	changes, err = productDB.FindPriceHistory(product.ID.String(), &start, &future)
+	assert.Nil(t, err)
+	assert.Len(t, changes, 2)
 }
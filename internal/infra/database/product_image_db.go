@@ -0,0 +1,88 @@
+package database
+
+import (
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ProductImage struct {
+	DB *gorm.DB
+}
+
+// Create stores the image record, refusing it once the product has
+// entity.MaxProductImages images. Like tags, images are part of the product
+// representation, so the product version is bumped.
+func (p *ProductImage) Create(image *entity.ProductImage) error {
+	return p.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&entity.ProductImage{}).Where("product_id = ?", image.ProductID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count >= entity.MaxProductImages {
+			return entity.ErrTooManyImages
+		}
+		if err := tx.Create(image).Error; err != nil {
+			return err
+		}
+		return bumpVersion(tx, image.ProductID.String())
+	})
+}
+
+// FindByProduct lists the images of a product in upload order.
+func (p *ProductImage) FindByProduct(productID string) ([]entity.ProductImage, error) {
+	var images []entity.ProductImage
+	err := p.DB.Where("product_id = ?", productID).Order("created_at, id").Find(&images).Error
+	return images, err
+}
+
+func (p *ProductImage) FindByID(productID, id string) (*entity.ProductImage, error) {
+	var image entity.ProductImage
+	err := p.DB.Where("id = ? AND product_id = ?", id, productID).First(&image).Error
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// Delete removes the image record; the caller deletes the stored files.
+func (p *ProductImage) Delete(productID, id string) error {
+	return p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND product_id = ?", id, productID).Delete(&entity.ProductImage{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return bumpVersion(tx, productID)
+	})
+}
+
+// loadImages fills the Images of every product with a single query.
+func (p *Product) loadImages(products []entity.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]string, len(products))
+	index := map[string]int{}
+	for i := range products {
+		ids[i] = products[i].ID.String()
+		index[ids[i]] = i
+		products[i].Images = []entity.ProductImage{}
+	}
+	var images []entity.ProductImage
+	err := p.DB.Where("product_id IN ?", ids).Order("created_at, id").Find(&images).Error
+	if err != nil {
+		return err
+	}
+	for _, image := range images {
+		i := index[image.ProductID.String()]
+		products[i].Images = append(products[i].Images, image)
+	}
+	return nil
+}
+
+func NewProductImage(db *gorm.DB) *ProductImage {
+	return &ProductImage{DB: db}
+}
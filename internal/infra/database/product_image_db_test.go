@@ -0,0 +1,56 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestProductImage_CreateAndDelete(t *testing.T) {
+	db := newProductTestDB(t)
+	productDB := NewProduct(db)
+	imageDB := NewProductImage(db)
+
+	product, _ := entity.NewProduct("Shirt", pkgEntity.Money{Amount: 1000, Currency: "BRL"})
+	productDB.Create(product)
+	first, _ := entity.NewProductImage(product.ID, "image/jpeg", "image/jpeg", 2048, 800, 600)
+	first.URL, first.ThumbnailURL = "http://localhost/a.jpg", "http://localhost/a_thumb.jpg"
+	assert.Nil(t, imageDB.Create(first))
+	second, _ := entity.NewProductImage(product.ID, "image/png", "image/png", 1024, 80, 60)
+	second.CreatedAt = first.CreatedAt.Add(1)
+	assert.Nil(t, imageDB.Create(second))
+
+	found, err := productDB.FindByID(product.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, found.Version)
+	assert.Len(t, found.Images, 2)
+	assert.Equal(t, first.ID, found.Images[0].ID)
+	assert.Equal(t, "http://localhost/a_thumb.jpg", found.Images[0].ThumbnailURL)
+	assert.Equal(t, first.Key, found.Images[0].Key)
+
+	_, err = imageDB.FindByID(pkgEntity.NewID().String(), first.ID.String())
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+	assert.Equal(t, gorm.ErrRecordNotFound, imageDB.Delete(pkgEntity.NewID().String(), first.ID.String()))
+	assert.Nil(t, imageDB.Delete(product.ID.String(), first.ID.String()))
+
+	images, err := imageDB.FindByProduct(product.ID.String())
+	assert.Nil(t, err)
+	assert.Len(t, images, 1)
+	assert.Equal(t, second.ID, images[0].ID)
+}
+
+func TestProductImage_Create_Limit(t *testing.T) {
+	db := newProductTestDB(t)
+	imageDB := NewProductImage(db)
+
+	productID := pkgEntity.NewID()
+	for i := 0; i < entity.MaxProductImages; i++ {
+		image, _ := entity.NewProductImage(productID, "image/png", "image/png", 10, 1, 1)
+		assert.Nil(t, imageDB.Create(image))
+	}
+	image, _ := entity.NewProductImage(productID, "image/png", "image/png", 10, 1, 1)
+	assert.Equal(t, entity.ErrTooManyImages, imageDB.Create(image))
+}
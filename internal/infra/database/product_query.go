@@ -0,0 +1,135 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var productSortColumns = map[string]string{
+	"name":       "name",
+	"price":      "price_amount",
+	"created_at": "created_at",
+}
+
+// ProductQuery describes a page of products. Zero values mean "no filter";
+// PriceCurrency keeps only products priced in that currency, and MinPrice
+// and MaxPrice are in its minor units. Amounts of different currencies
+// cannot be compared, so callers set PriceCurrency along with the price
+// filters or a price sort.
+// Category limits the products to that category, or to it and every
+// subcategory below it when IncludeDescendants is set. Tags keeps products
+// with any of the tags, or with all of them when TagMode is "all"; tags must
+// already be normalized and unique. SortBy must be one of name, price or
+// created_at and SortDir asc or desc, anything else falls back to created_at
+// asc.
+type ProductQuery struct {
+	Page               int
+	Limit              int
+	Search             string
+	MinPrice           *int64
+	MaxPrice           *int64
+	PriceCurrency      string
+	CreatedAfter       *time.Time
+	CreatedBefore      *time.Time
+	OwnerID            string
+	Category           string
+	IncludeDescendants bool
+	Tags               []string
+	TagMode            string
+	SortBy             string
+	SortDir            string
+}
+
+// FilterHash identifies the filters of q, leaving out paging and sorting,
+// so that a cursor can be tied to the listing it was issued for.
+func (q ProductQuery) FilterHash() string {
+	filters, _ := json.Marshal([]interface{}{
+		q.Search, q.MinPrice, q.MaxPrice, q.PriceCurrency, q.CreatedAfter, q.CreatedBefore,
+		q.OwnerID, q.Category, q.IncludeDescendants, q.Tags, q.TagMode,
+	})
+	sum := sha256.Sum256(filters)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// CursorAfter is the position after product in the listing q describes;
+// q must be normalized.
+func (q ProductQuery) CursorAfter(product entity.Product) pkgEntity.Cursor {
+	return pkgEntity.Cursor{CreatedAt: product.CreatedAt, ID: product.ID, SortDir: q.SortDir, Filter: q.FilterHash()}
+}
+
+func IsValidProductSort(sortBy string) bool {
+	_, ok := productSortColumns[sortBy]
+	return ok
+}
+
+// Normalize applies the defaults FindAll uses, so callers can report the
+// page and limit that were actually served.
+func (q ProductQuery) Normalize() ProductQuery {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 10 {
+		q.Limit = 10
+	}
+	if !IsValidProductSort(q.SortBy) {
+		q.SortBy = "created_at"
+	}
+	if q.SortDir != "desc" {
+		q.SortDir = "asc"
+	}
+	return q
+}
+
+func (q ProductQuery) filter(db *gorm.DB) *gorm.DB {
+	if q.Search != "" {
+		db = db.Where("LOWER(name) LIKE ? ESCAPE '!'", "%"+escapeLike(strings.ToLower(q.Search))+"%")
+	}
+	if q.PriceCurrency != "" {
+		db = db.Where("price_currency = ?", q.PriceCurrency)
+	}
+	if q.MinPrice != nil {
+		db = db.Where("price_amount >= ?", *q.MinPrice)
+	}
+	if q.MaxPrice != nil {
+		db = db.Where("price_amount <= ?", *q.MaxPrice)
+	}
+	if q.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *q.CreatedAfter)
+	}
+	if q.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *q.CreatedBefore)
+	}
+	if q.OwnerID != "" {
+		db = db.Where("owner_id = ?", q.OwnerID)
+	}
+	if q.Category != "" && q.IncludeDescendants {
+		db = db.Where("id IN (SELECT product_id FROM product_categories WHERE category_id IN ("+categorySubtree+"))", q.Category)
+	} else if q.Category != "" {
+		db = db.Where("id IN (SELECT product_id FROM product_categories WHERE category_id = ?)", q.Category)
+	}
+	if len(q.Tags) > 0 && q.TagMode == "all" {
+		db = db.Where("id IN (SELECT product_id FROM product_tags WHERE tag IN ? GROUP BY product_id HAVING COUNT(*) = ?)", q.Tags, len(q.Tags))
+	} else if len(q.Tags) > 0 {
+		db = db.Where("id IN (SELECT product_id FROM product_tags WHERE tag IN ?)", q.Tags)
+	}
+	return db
+}
+
+func (q ProductQuery) order(db *gorm.DB) *gorm.DB {
+	desc := q.SortDir == "desc"
+	return db.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: productSortColumns[q.SortBy]}, Desc: desc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: desc})
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+// productTag links a product to one of its tags. Tags have no table of
+// their own: a tag exists while some product carries it.
+type productTag struct {
+	ProductID string `gorm:"primaryKey"`
+	Tag       string `gorm:"primaryKey"`
+}
+
+func (productTag) TableName() string {
+	return "product_tags"
+}
+
+// AddTags adds the tags the product does not have yet. Any change bumps the
+// product version since tags are part of its representation.
+func (p *Product) AddTags(id string, tags []string) error {
+	return p.DB.Transaction(func(tx *gorm.DB) error {
+		current, err := productTags(tx, id)
+		if err != nil {
+			return err
+		}
+		has := map[string]bool{}
+		for _, tag := range current {
+			has[tag] = true
+		}
+		var links []productTag
+		for _, tag := range tags {
+			if !has[tag] {
+				has[tag] = true
+				links = append(links, productTag{ProductID: id, Tag: tag})
+			}
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return err
+		}
+		return bumpVersion(tx, id)
+	})
+}
+
+// RemoveTags removes the given tags from the product, ignoring the ones it
+// does not have.
+func (p *Product) RemoveTags(id string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return p.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("product_id = ? AND tag IN ?", id, tags).Delete(&productTag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return bumpVersion(tx, id)
+	})
+}
+
+// FindTags lists every tag in use by a product that is not in the trash,
+// with the number of such products, ordered by tag.
+func (p *Product) FindTags() ([]entity.TagUsage, error) {
+	var usages []entity.TagUsage
+	err := p.DB.Table("product_tags").
+		Select("product_tags.tag AS tag, COUNT(*) AS count").
+		Joins("JOIN products ON products.id = product_tags.product_id").
+		Where("products.deleted_at IS NULL").
+		Group("product_tags.tag").
+		Order("product_tags.tag").
+		Scan(&usages).Error
+	return usages, err
+}
+
+// loadTags fills the Tags of every product with a single query.
+func (p *Product) loadTags(products []entity.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]string, len(products))
+	index := map[string]int{}
+	for i := range products {
+		ids[i] = products[i].ID.String()
+		index[ids[i]] = i
+		products[i].Tags = []string{}
+	}
+	var links []productTag
+	err := p.DB.Where("product_id IN ?", ids).Order("tag").Find(&links).Error
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		i := index[link.ProductID]
+		products[i].Tags = append(products[i].Tags, link.Tag)
+	}
+	return nil
+}
+
+func productTags(tx *gorm.DB, id string) ([]string, error) {
+	var tags []string
+	err := tx.Model(&productTag{}).Where("product_id = ?", id).Order("tag").Pluck("tag", &tags).Error
+	return tags, err
+}
+
+func bumpVersion(tx *gorm.DB, id string) error {
+	return tx.Unscoped().Model(&entity.Product{}).Where("id = ?", id).
+		Update("version", gorm.Expr("version + 1")).Error
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newProductTagDB(t *testing.T) *Product {
+	db := newProductTestDB(t)
+	return NewProduct(db)
+}
+
+func TestProduct_CreateWithTags(t *testing.T) {
+	productDB := newProductTagDB(t)
+	product, _ := entity.NewProduct("Blue Shirt", brl(100))
+	product.Tags = []string{"summer", "sale"}
+	assert.Nil(t, productDB.Create(product))
+
+	found, err := productDB.FindByID(product.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"sale", "summer"}, found.Tags)
+
+	other, _ := entity.NewProduct("Red Shirt", brl(100))
+	assert.Nil(t, productDB.Create(other))
+	found, err = productDB.FindByID(other.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, found.Tags)
+}
+
+func TestProduct_AddAndRemoveTags(t *testing.T) {
+	productDB := newProductTagDB(t)
+	product, _ := entity.NewProduct("Blue Shirt", brl(100))
+	assert.Nil(t, productDB.Create(product))
+	id := product.ID.String()
+
+	assert.Nil(t, productDB.AddTags(id, []string{"sale", "summer"}))
+	assert.Nil(t, productDB.AddTags(id, []string{"sale"}))
+	found, _ := productDB.FindByID(id)
+	assert.Equal(t, []string{"sale", "summer"}, found.Tags)
+	assert.Equal(t, 2, found.Version)
+
+	assert.Nil(t, productDB.RemoveTags(id, []string{"sale", "winter"}))
+	assert.Nil(t, productDB.RemoveTags(id, []string{"winter"}))
+	found, _ = productDB.FindByID(id)
+	assert.Equal(t, []string{"summer"}, found.Tags)
+	assert.Equal(t, 3, found.Version)
+}
+
+func TestProduct_FindTags(t *testing.T) {
+	productDB := newProductTagDB(t)
+	for name, tags := range map[string][]string{
+		"Blue Shirt": {"sale", "summer"},
+		"Red Shirt":  {"sale"},
+		"Old Shirt":  {"clearance", "sale"},
+	} {
+		product, _ := entity.NewProduct(name, brl(100))
+		product.Tags = tags
+		assert.Nil(t, productDB.Create(product))
+		if name == "Old Shirt" {
+			assert.Nil(t, productDB.Delete(product.ID.String(), product.Version))
+		}
+	}
+
+	usages, err := productDB.FindTags()
+	assert.Nil(t, err)
+	assert.Equal(t, []entity.TagUsage{{Tag: "sale", Count: 2}, {Tag: "summer", Count: 1}}, usages)
+}
+
+func TestProduct_FindAll_ByTags(t *testing.T) {
+	productDB := newProductTagDB(t)
+	for name, tags := range map[string][]string{
+		"Blue Shirt": {"sale", "summer"},
+		"Red Shirt":  {"sale"},
+		"Boots":      {"winter"},
+	} {
+		product, _ := entity.NewProduct(name, brl(100))
+		product.Tags = tags
+		assert.Nil(t, productDB.Create(product))
+	}
+
+	products, total, err := productDB.FindAll(ProductQuery{Tags: []string{"summer", "winter"}, SortBy: "name"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "Blue Shirt", products[0].Name)
+	assert.Equal(t, "Boots", products[1].Name)
+
+	products, total, err = productDB.FindAll(ProductQuery{Tags: []string{"sale", "summer"}, TagMode: "all"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "Blue Shirt", products[0].Name)
+	assert.Equal(t, []string{"sale", "summer"}, products[0].Tags)
+}
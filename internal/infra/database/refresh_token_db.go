@@ -0,0 +1,55 @@
+package database
+
+import (
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type RefreshToken struct {
+	DB *gorm.DB
+}
+
+func (rt *RefreshToken) Create(token *entity.RefreshToken) error {
+	return rt.DB.Create(token).Error
+}
+
+func (rt *RefreshToken) FindByHash(hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := rt.DB.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marks current as used and stores next in the same transaction. The
+// conditional update makes concurrent rotations of one token race safely:
+// only one wins, the other gets ErrRefreshTokenReused.
+func (rt *RefreshToken) Rotate(current, next *entity.RefreshToken) error {
+	return rt.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("rotated_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrRefreshTokenReused
+		}
+		current.RotatedAt = &now
+		return tx.Create(next).Error
+	})
+}
+
+func (rt *RefreshToken) RevokeFamily(familyID string) error {
+	return rt.DB.Model(&entity.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func NewRefreshToken(db *gorm.DB) *RefreshToken {
+	return &RefreshToken{DB: db}
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRefreshToken_FindByHash(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.RefreshToken{})
+	refreshTokenDB := NewRefreshToken(db)
+
+	rt, token, _ := entity.NewRefreshToken(pkgEntity.NewID(), pkgEntity.ID{}, 60)
+	err = refreshTokenDB.Create(rt)
+	assert.Nil(t, err)
+
+	found, err := refreshTokenDB.FindByHash(pkgEntity.HashToken(token))
+	assert.Nil(t, err)
+	assert.Equal(t, rt.ID, found.ID)
+	assert.Equal(t, rt.FamilyID, found.FamilyID)
+	assert.Nil(t, found.RotatedAt)
+
+	_, err = refreshTokenDB.FindByHash(token)
+	assert.NotNil(t, err)
+}
+
+func TestRefreshToken_Rotate(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.RefreshToken{})
+	refreshTokenDB := NewRefreshToken(db)
+
+	current, token, _ := entity.NewRefreshToken(pkgEntity.NewID(), pkgEntity.ID{}, 60)
+	refreshTokenDB.Create(current)
+	next, _, _ := entity.NewRefreshToken(current.UserID, current.FamilyID, 60)
+
+	err = refreshTokenDB.Rotate(current, next)
+	assert.Nil(t, err)
+	found, _ := refreshTokenDB.FindByHash(pkgEntity.HashToken(token))
+	assert.NotNil(t, found.RotatedAt)
+	assert.Equal(t, entity.ErrRefreshTokenReused, found.Validate())
+
+	another, _, _ := entity.NewRefreshToken(current.UserID, current.FamilyID, 60)
+	err = refreshTokenDB.Rotate(current, another)
+	assert.Equal(t, entity.ErrRefreshTokenReused, err)
+}
+
+func TestRefreshToken_RevokeFamily(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.RefreshToken{})
+	refreshTokenDB := NewRefreshToken(db)
+
+	first, _, _ := entity.NewRefreshToken(pkgEntity.NewID(), pkgEntity.ID{}, 60)
+	refreshTokenDB.Create(first)
+	second, secondToken, _ := entity.NewRefreshToken(first.UserID, first.FamilyID, 60)
+	refreshTokenDB.Rotate(first, second)
+
+	err = refreshTokenDB.RevokeFamily(first.FamilyID.String())
+	assert.Nil(t, err)
+
+	found, _ := refreshTokenDB.FindByHash(pkgEntity.HashToken(secondToken))
+	assert.NotNil(t, found.RevokedAt)
+	assert.Equal(t, entity.ErrRefreshTokenReused, found.Validate())
+}
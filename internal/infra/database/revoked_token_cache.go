@@ -0,0 +1,95 @@
+package database
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type revokedTokenEntry struct {
+	jti       string
+	revoked   bool
+	expiresAt time.Time
+}
+
+// RevokedTokenCache is an in-memory LRU in front of another revocation store.
+// Revocations are written through, so entries for revoked tokens are kept
+// until the token expires. Negative lookups are only trusted for NegativeTTL,
+// which bounds how long a revocation made by another instance goes unseen.
+type RevokedTokenCache struct {
+	Store       RevokedTokenInterface
+	Size        int
+	NegativeTTL time.Duration
+
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func (c *RevokedTokenCache) Revoke(jti string, expiresAt time.Time) error {
+	if err := c.Store.Revoke(jti, expiresAt); err != nil {
+		return err
+	}
+	c.set(jti, true, expiresAt)
+	return nil
+}
+
+func (c *RevokedTokenCache) IsRevoked(jti string) (bool, error) {
+	if revoked, ok := c.get(jti); ok {
+		return revoked, nil
+	}
+	revoked, err := c.Store.IsRevoked(jti)
+	if err != nil {
+		return false, err
+	}
+	c.set(jti, revoked, time.Now().Add(c.NegativeTTL))
+	return revoked, nil
+}
+
+func (c *RevokedTokenCache) get(jti string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[jti]
+	if !ok {
+		return false, false
+	}
+	entry := el.Value.(*revokedTokenEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, jti)
+		return false, false
+	}
+	c.order.MoveToFront(el)
+	return entry.revoked, true
+}
+
+func (c *RevokedTokenCache) set(jti string, revoked bool, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[jti]; ok {
+		entry := el.Value.(*revokedTokenEntry)
+		entry.revoked = revoked
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[jti] = c.order.PushFront(&revokedTokenEntry{jti: jti, revoked: revoked, expiresAt: expiresAt})
+	for c.order.Len() > c.Size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*revokedTokenEntry).jti)
+	}
+}
+
+func NewRevokedTokenCache(store RevokedTokenInterface, size int, negativeTTL time.Duration) *RevokedTokenCache {
+	if size < 1 {
+		size = 1
+	}
+	return &RevokedTokenCache{
+		Store:       store,
+		Size:        size,
+		NegativeTTL: negativeTTL,
+		order:       list.New(),
+		entries:     make(map[string]*list.Element),
+	}
+}
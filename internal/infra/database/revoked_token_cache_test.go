@@ -0,0 +1,86 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type revokedTokenStoreStub struct {
+	revoked map[string]bool
+	lookups int
+}
+
+func (s *revokedTokenStoreStub) Revoke(jti string, expiresAt time.Time) error {
+	s.revoked[jti] = true
+	return nil
+}
+
+func (s *revokedTokenStoreStub) IsRevoked(jti string) (bool, error) {
+	s.lookups++
+	return s.revoked[jti], nil
+}
+
+func TestRevokedTokenCache_IsRevoked(t *testing.T) {
+	store := &revokedTokenStoreStub{revoked: map[string]bool{"leaked": true}}
+	cache := NewRevokedTokenCache(store, 10, time.Minute)
+
+	revoked, err := cache.IsRevoked("leaked")
+	assert.Nil(t, err)
+	assert.True(t, revoked)
+	revoked, _ = cache.IsRevoked("leaked")
+	assert.True(t, revoked)
+	assert.Equal(t, 1, store.lookups)
+
+	revoked, _ = cache.IsRevoked("valid")
+	assert.False(t, revoked)
+	revoked, _ = cache.IsRevoked("valid")
+	assert.False(t, revoked)
+	assert.Equal(t, 2, store.lookups)
+}
+
+func TestRevokedTokenCache_Revoke(t *testing.T) {
+	store := &revokedTokenStoreStub{revoked: map[string]bool{}}
+	cache := NewRevokedTokenCache(store, 10, time.Minute)
+
+	revoked, _ := cache.IsRevoked("jti-1")
+	assert.False(t, revoked)
+
+	err := cache.Revoke("jti-1", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.True(t, store.revoked["jti-1"])
+
+	revoked, _ = cache.IsRevoked("jti-1")
+	assert.True(t, revoked)
+	assert.Equal(t, 1, store.lookups)
+}
+
+func TestRevokedTokenCache_Eviction(t *testing.T) {
+	store := &revokedTokenStoreStub{revoked: map[string]bool{}}
+	cache := NewRevokedTokenCache(store, 2, time.Minute)
+
+	cache.IsRevoked("a")
+	cache.IsRevoked("b")
+	cache.IsRevoked("a")
+	cache.IsRevoked("c")
+	assert.Equal(t, 3, store.lookups)
+
+	cache.IsRevoked("a")
+	assert.Equal(t, 3, store.lookups)
+	cache.IsRevoked("b")
+	assert.Equal(t, 4, store.lookups)
+}
+
+func TestRevokedTokenCache_NegativeTTL(t *testing.T) {
+	store := &revokedTokenStoreStub{revoked: map[string]bool{}}
+	cache := NewRevokedTokenCache(store, 10, time.Millisecond)
+
+	cache.IsRevoked("jti-1")
+	store.revoked["jti-1"] = true
+	time.Sleep(5 * time.Millisecond)
+
+	revoked, _ := cache.IsRevoked("jti-1")
+	assert.True(t, revoked)
+	assert.Equal(t, 2, store.lookups)
+}
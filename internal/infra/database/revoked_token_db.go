@@ -0,0 +1,37 @@
+package database
+
+import (
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RevokedToken struct {
+	DB *gorm.DB
+}
+
+func (rt *RevokedToken) Revoke(jti string, expiresAt time.Time) error {
+	return rt.DB.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(entity.NewRevokedToken(jti, expiresAt)).Error
+}
+
+func (rt *RevokedToken) IsRevoked(jti string) (bool, error) {
+	var count int64
+	err := rt.DB.Model(&entity.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteExpired drops tokens that have expired on their own, since the
+// token verifier rejects them without consulting the denylist.
+func (rt *RevokedToken) DeleteExpired() error {
+	return rt.DB.Where("expires_at < ?", time.Now()).Delete(&entity.RevokedToken{}).Error
+}
+
+func NewRevokedToken(db *gorm.DB) *RevokedToken {
+	return &RevokedToken{DB: db}
+}
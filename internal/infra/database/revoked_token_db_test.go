@@ -0,0 +1,53 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRevokedToken_Revoke(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.RevokedToken{})
+	revokedTokenDB := NewRevokedToken(db)
+
+	revoked, err := revokedTokenDB.IsRevoked("jti-1")
+	assert.Nil(t, err)
+	assert.False(t, revoked)
+
+	err = revokedTokenDB.Revoke("jti-1", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	err = revokedTokenDB.Revoke("jti-1", time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+
+	revoked, err = revokedTokenDB.IsRevoked("jti-1")
+	assert.Nil(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRevokedToken_DeleteExpired(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.RevokedToken{})
+	revokedTokenDB := NewRevokedToken(db)
+
+	revokedTokenDB.Revoke("expired", time.Now().Add(-time.Minute))
+	revokedTokenDB.Revoke("active", time.Now().Add(time.Minute))
+
+	err = revokedTokenDB.DeleteExpired()
+	assert.Nil(t, err)
+
+	revoked, _ := revokedTokenDB.IsRevoked("expired")
+	assert.False(t, revoked)
+	revoked, _ = revokedTokenDB.IsRevoked("active")
+	assert.True(t, revoked)
+}
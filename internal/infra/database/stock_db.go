@@ -0,0 +1,279 @@
+package database
+
+import (
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Stock changes product stock levels. Every change is a single conditional
+// UPDATE, so concurrent requests can never take stock below zero.
+type Stock struct {
+	DB *gorm.DB
+}
+
+// Adjust applies the adjustment to the product's stock, and to its quantity
+// in the adjustment's warehouse if any, and records it.
+func (s *Stock) Adjust(adjustment *entity.StockAdjustment) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := changeStock(tx, adjustment.ProductID.String(), adjustment.Delta); err != nil {
+			return err
+		}
+		if movement := adjustment.Movement(); movement != nil {
+			if err := move(tx, movement); err != nil {
+				return err
+			}
+		}
+		return tx.Create(adjustment).Error
+	})
+}
+
+// Transfer moves stock between warehouses. The product's stock available
+// for sale does not change.
+func (s *Stock) Transfer(movements [2]entity.StockMovement) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&entity.Product{}).Where("id = ?", movements[0].ProductID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		for i := range movements {
+			if err := move(tx, &movements[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindMovements lists the warehouse movements of a product, newest first.
+func (s *Stock) FindMovements(productID string) ([]entity.StockMovement, error) {
+	var movements []entity.StockMovement
+	err := s.DB.Where("product_id = ?", productID).
+		Order("created_at DESC").Order("delta").Order("id").
+		Find(&movements).Error
+	return movements, err
+}
+
+// FindLocations lists the warehouses holding the product, by warehouse code.
+func (s *Stock) FindLocations(productID string) ([]entity.StockLocation, error) {
+	var locations []entity.StockLocation
+	err := s.DB.Model(&entity.WarehouseStock{}).
+		Select("warehouse_stock.warehouse_id, warehouses.code, warehouses.name, warehouse_stock.quantity").
+		Joins("JOIN warehouses ON warehouses.id = warehouse_stock.warehouse_id").
+		Where("warehouse_stock.product_id = ? AND warehouse_stock.quantity > 0", productID).
+		Order("warehouses.code").
+		Scan(&locations).Error
+	return locations, err
+}
+
+// FindAdjustments lists the adjustments of a product, newest first.
+func (s *Stock) FindAdjustments(productID string) ([]entity.StockAdjustment, error) {
+	var adjustments []entity.StockAdjustment
+	err := s.DB.Where("product_id = ?", productID).
+		Order("created_at DESC").Order("id DESC").
+		Find(&adjustments).Error
+	return adjustments, err
+}
+
+// Reserve takes the reservation's quantity out of the product's stock. Held
+// reservations of the product that have expired are released first.
+func (s *Stock) Reserve(reservation *entity.Reservation) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := releaseExpired(tx, tx.Where("product_id = ?", reservation.ProductID), time.Now())
+		if err != nil {
+			return err
+		}
+		if err := changeStock(tx, reservation.ProductID.String(), -reservation.Quantity); err != nil {
+			return err
+		}
+		return tx.Create(reservation).Error
+	})
+}
+
+func (s *Stock) FindReservation(id string) (*entity.Reservation, error) {
+	var reservation entity.Reservation
+	err := s.DB.Where("id = ?", id).First(&reservation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// Commit makes a held reservation final; its quantity stays out of stock.
+// An expired reservation is released instead and ErrReservationExpired
+// returned.
+func (s *Stock) Commit(id string) error {
+	now := time.Now()
+	result := s.DB.Model(&entity.Reservation{}).
+		Where("id = ? AND status = ? AND expires_at > ?", id, entity.ReservationHeld, now).
+		Update("status", entity.ReservationCommitted)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	reservation, err := s.FindReservation(id)
+	if err != nil {
+		return err
+	}
+	if !reservation.IsExpired(now) {
+		return entity.ErrReservationNotHeld
+	}
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		_, err := releaseExpired(tx, tx.Where("id = ?", id), now)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return entity.ErrReservationExpired
+}
+
+// Release returns a held reservation's quantity to stock.
+func (s *Stock) Release(id string) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		released, err := releaseHeld(tx, id)
+		if err != nil {
+			return err
+		}
+		if !released {
+			return entity.ErrReservationNotHeld
+		}
+		return nil
+	})
+}
+
+// ReleaseExpired releases every held reservation whose TTL has passed and
+// returns how many were released.
+func (s *Stock) ReleaseExpired() (int, error) {
+	var released int
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		released, err = releaseExpired(tx, tx, time.Now())
+		return err
+	})
+	return released, err
+}
+
+// Reserved sums the quantity held by unexpired reservations of a product.
+func (s *Stock) Reserved(productID string) (int64, error) {
+	var reserved int64
+	err := s.DB.Model(&entity.Reservation{}).
+		Where("product_id = ? AND status = ? AND expires_at > ?", productID, entity.ReservationHeld, time.Now()).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&reserved).Error
+	return reserved, err
+}
+
+// changeStock adds delta to the product's stock unless that would make it
+// negative, in which case it returns ErrInsufficientStock.
+func changeStock(tx *gorm.DB, productID string, delta int64) error {
+	result := tx.Model(&entity.Product{}).
+		Where("id = ? AND stock + ? >= 0", productID, delta).
+		Update("stock", gorm.Expr("stock + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&entity.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return entity.ErrInsufficientStock
+}
+
+// move applies a movement to the warehouse's quantity, refusing to take it
+// below zero, and records the movement.
+func move(tx *gorm.DB, movement *entity.StockMovement) error {
+	var count int64
+	err := tx.Model(&entity.Warehouse{}).Where("id = ?", movement.WarehouseID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return entity.ErrWarehouseNotFound
+	}
+
+	if movement.Delta > 0 {
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "warehouse_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"quantity": gorm.Expr("warehouse_stock.quantity + ?", movement.Delta)}),
+		}).Create(&entity.WarehouseStock{
+			ProductID:   movement.ProductID,
+			WarehouseID: movement.WarehouseID,
+			Quantity:    movement.Delta,
+		}).Error
+		if err != nil {
+			return err
+		}
+	} else {
+		result := tx.Model(&entity.WarehouseStock{}).
+			Where("product_id = ? AND warehouse_id = ? AND quantity + ? >= 0", movement.ProductID, movement.WarehouseID, movement.Delta).
+			Update("quantity", gorm.Expr("quantity + ?", movement.Delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrInsufficientStock
+		}
+	}
+	return tx.Create(movement).Error
+}
+
+// releaseHeld flips a held reservation to released and puts its quantity
+// back. It reports false when the reservation was no longer held, so two
+// concurrent releases only return the stock once.
+func releaseHeld(tx *gorm.DB, id string) (bool, error) {
+	var reservation entity.Reservation
+	if err := tx.Where("id = ?", id).First(&reservation).Error; err != nil {
+		return false, err
+	}
+	result := tx.Model(&entity.Reservation{}).
+		Where("id = ? AND status = ?", id, entity.ReservationHeld).
+		Update("status", entity.ReservationReleased)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	// The product may be in the trash; stock is returned all the same.
+	err := tx.Unscoped().Model(&entity.Product{}).Where("id = ?", reservation.ProductID).
+		Update("stock", gorm.Expr("stock + ?", reservation.Quantity)).Error
+	return err == nil, err
+}
+
+// releaseExpired releases the expired held reservations selected by scope
+// and returns how many it released.
+func releaseExpired(tx *gorm.DB, scope *gorm.DB, now time.Time) (int, error) {
+	var ids []string
+	err := scope.Model(&entity.Reservation{}).
+		Where("status = ? AND expires_at <= ?", entity.ReservationHeld, now).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, err
+	}
+	released := 0
+	for _, id := range ids {
+		ok, err := releaseHeld(tx, id)
+		if err != nil {
+			return released, err
+		}
+		if ok {
+			released++
+		}
+	}
+	return released, nil
+}
+
+func NewStock(db *gorm.DB) *Stock {
+	return &Stock{DB: db}
+}
@@ -0,0 +1,196 @@
+package database
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newStockDB(t *testing.T, dsn string) (*Stock, *entity.Product) {
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.Product{}, &entity.StockAdjustment{}, &entity.Reservation{}, &entity.Warehouse{}, &entity.WarehouseStock{}, &entity.StockMovement{})
+	product, _ := entity.NewProduct("Product 1", brl(100))
+	product.Stock = 10
+	db.Create(product)
+	return NewStock(db), product
+}
+
+func stockOf(t *testing.T, s *Stock, product *entity.Product) int64 {
+	var found entity.Product
+	assert.Nil(t, s.DB.Unscoped().First(&found, "id = ?", product.ID).Error)
+	return found.Stock
+}
+
+func TestStock_Adjust(t *testing.T) {
+	stockDB, product := newStockDB(t, "file::memory:")
+
+	adjustment, _ := entity.NewStockAdjustment(product.ID, nil, 5, entity.StockReasonReceived, "", nil)
+	assert.Nil(t, stockDB.Adjust(adjustment))
+	assert.Equal(t, int64(15), stockOf(t, stockDB, product))
+
+	adjustment, _ = entity.NewStockAdjustment(product.ID, nil, -16, entity.StockReasonDamaged, "", nil)
+	assert.Equal(t, entity.ErrInsufficientStock, stockDB.Adjust(adjustment))
+	assert.Equal(t, int64(15), stockOf(t, stockDB, product))
+
+	missing, _ := entity.NewProduct("Product 2", brl(100))
+	adjustment, _ = entity.NewStockAdjustment(missing.ID, nil, 1, entity.StockReasonReceived, "", nil)
+	assert.Equal(t, gorm.ErrRecordNotFound, stockDB.Adjust(adjustment))
+
+	adjustments, err := stockDB.FindAdjustments(product.ID.String())
+	assert.Nil(t, err)
+	assert.Len(t, adjustments, 1)
+	assert.Equal(t, int64(5), adjustments[0].Delta)
+}
+
+func TestStock_AdjustConcurrently(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_busy_timeout=5000&_txlock=immediate"
+	stockDB, product := newStockDB(t, dsn)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	results := map[error]int{}
+	for i := 0; i < 25; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			adjustment, _ := entity.NewStockAdjustment(product.ID, nil, -1, entity.StockReasonSold, "", nil)
+			err := stockDB.Adjust(adjustment)
+			mu.Lock()
+			results[err]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[error]int{nil: 10, entity.ErrInsufficientStock: 15}, results)
+	assert.Equal(t, int64(0), stockOf(t, stockDB, product))
+}
+
+func TestStock_ReserveAndCommit(t *testing.T) {
+	stockDB, product := newStockDB(t, "file::memory:")
+
+	reservation, _ := entity.NewReservation(product.ID, 4, time.Minute, nil)
+	assert.Nil(t, stockDB.Reserve(reservation))
+	assert.Equal(t, int64(6), stockOf(t, stockDB, product))
+	reserved, err := stockDB.Reserved(product.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), reserved)
+
+	tooMany, _ := entity.NewReservation(product.ID, 7, time.Minute, nil)
+	assert.Equal(t, entity.ErrInsufficientStock, stockDB.Reserve(tooMany))
+
+	assert.Nil(t, stockDB.Commit(reservation.ID.String()))
+	assert.Equal(t, entity.ErrReservationNotHeld, stockDB.Commit(reservation.ID.String()))
+	assert.Equal(t, entity.ErrReservationNotHeld, stockDB.Release(reservation.ID.String()))
+	assert.Equal(t, int64(6), stockOf(t, stockDB, product))
+
+	found, err := stockDB.FindReservation(reservation.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, entity.ReservationCommitted, found.Status)
+}
+
+func TestStock_Release(t *testing.T) {
+	stockDB, product := newStockDB(t, "file::memory:")
+	reservation, _ := entity.NewReservation(product.ID, 4, time.Minute, nil)
+	assert.Nil(t, stockDB.Reserve(reservation))
+
+	assert.Nil(t, stockDB.Release(reservation.ID.String()))
+	assert.Equal(t, int64(10), stockOf(t, stockDB, product))
+	assert.Equal(t, entity.ErrReservationNotHeld, stockDB.Release(reservation.ID.String()))
+	assert.Equal(t, int64(10), stockOf(t, stockDB, product))
+	assert.Equal(t, gorm.ErrRecordNotFound, stockDB.Release(product.ID.String()))
+}
+
+func TestStock_Expiry(t *testing.T) {
+	stockDB, product := newStockDB(t, "file::memory:")
+	expired, _ := entity.NewReservation(product.ID, 4, time.Minute, nil)
+	assert.Nil(t, stockDB.Reserve(expired))
+	other, _ := entity.NewReservation(product.ID, 3, time.Minute, nil)
+	assert.Nil(t, stockDB.Reserve(other))
+	stockDB.DB.Model(&entity.Reservation{}).Where("id IN ?", []string{expired.ID.String(), other.ID.String()}).
+		Update("expires_at", time.Now().Add(-time.Second))
+
+	assert.Equal(t, entity.ErrReservationExpired, stockDB.Commit(expired.ID.String()))
+	assert.Equal(t, int64(7), stockOf(t, stockDB, product))
+
+	released, err := stockDB.ReleaseExpired()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, int64(10), stockOf(t, stockDB, product))
+
+	reserved, _ := stockDB.Reserved(product.ID.String())
+	assert.Equal(t, int64(0), reserved)
+}
+
+func newWarehouses(t *testing.T, s *Stock, codes ...string) []*entity.Warehouse {
+	var warehouses []*entity.Warehouse
+	for _, code := range codes {
+		warehouse, _ := entity.NewWarehouse(code, code)
+		assert.Nil(t, s.DB.Create(warehouse).Error)
+		warehouses = append(warehouses, warehouse)
+	}
+	return warehouses
+}
+
+func TestStock_AdjustAtWarehouse(t *testing.T) {
+	stockDB, product := newStockDB(t, "file::memory:")
+	sp := newWarehouses(t, stockDB, "SP-01")[0]
+
+	adjustment, _ := entity.NewStockAdjustment(product.ID, &sp.ID, 5, entity.StockReasonReceived, "", nil)
+	assert.Nil(t, stockDB.Adjust(adjustment))
+	adjustment, _ = entity.NewStockAdjustment(product.ID, &sp.ID, 3, entity.StockReasonReceived, "", nil)
+	assert.Nil(t, stockDB.Adjust(adjustment))
+	assert.Equal(t, int64(18), stockOf(t, stockDB, product))
+
+	adjustment, _ = entity.NewStockAdjustment(product.ID, &sp.ID, -9, entity.StockReasonLost, "", nil)
+	assert.Equal(t, entity.ErrInsufficientStock, stockDB.Adjust(adjustment))
+	assert.Equal(t, int64(18), stockOf(t, stockDB, product))
+
+	missing := entity.Warehouse{ID: product.ID}
+	adjustment, _ = entity.NewStockAdjustment(product.ID, &missing.ID, 1, entity.StockReasonReceived, "", nil)
+	assert.Equal(t, entity.ErrWarehouseNotFound, stockDB.Adjust(adjustment))
+
+	locations, err := stockDB.FindLocations(product.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, []entity.StockLocation{{WarehouseID: sp.ID, Code: "SP-01", Name: "SP-01", Quantity: 8}}, locations)
+	movements, err := stockDB.FindMovements(product.ID.String())
+	assert.Nil(t, err)
+	assert.Len(t, movements, 2)
+}
+
+func TestStock_Transfer(t *testing.T) {
+	stockDB, product := newStockDB(t, "file::memory:")
+	warehouses := newWarehouses(t, stockDB, "SP-01", "RJ-01")
+	sp, rj := warehouses[0], warehouses[1]
+	adjustment, _ := entity.NewStockAdjustment(product.ID, &sp.ID, 5, entity.StockReasonReceived, "", nil)
+	assert.Nil(t, stockDB.Adjust(adjustment))
+
+	movements, _ := entity.NewStockTransfer(product.ID, sp.ID, rj.ID, 2, nil)
+	assert.Nil(t, stockDB.Transfer(movements))
+	movements, _ = entity.NewStockTransfer(product.ID, sp.ID, rj.ID, 4, nil)
+	assert.Equal(t, entity.ErrInsufficientStock, stockDB.Transfer(movements))
+
+	locations, err := stockDB.FindLocations(product.ID.String())
+	assert.Nil(t, err)
+	assert.Len(t, locations, 2)
+	assert.Equal(t, "RJ-01", locations[0].Code)
+	assert.Equal(t, int64(2), locations[0].Quantity)
+	assert.Equal(t, int64(3), locations[1].Quantity)
+	assert.Equal(t, int64(15), stockOf(t, stockDB, product))
+
+	history, err := stockDB.FindMovements(product.ID.String())
+	assert.Nil(t, err)
+	assert.Len(t, history, 3)
+	assert.Equal(t, int64(-2), history[0].Delta)
+	assert.Equal(t, int64(2), history[1].Delta)
+	assert.Equal(t, history[0].TransferID, history[1].TransferID)
+}
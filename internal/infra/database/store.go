@@ -0,0 +1,45 @@
+package database
+
+import "gorm.io/gorm"
+
+// Repositories are bound to one transaction by Store.Transaction, so that
+// a write and the audit entry describing it are committed together.
+type Repositories struct {
+	UserDB      UserInterface
+	ProductDB   ProductInterface
+	CategoryDB  CategoryInterface
+	WarehouseDB WarehouseInterface
+	VariantDB   VariantInterface
+	ImageDB     ProductImageInterface
+	CartDB      CartInterface
+	AuditDB     AuditInterface
+}
+
+type StoreInterface interface {
+	Transaction(fn func(repos Repositories) error) error
+}
+
+type Store struct {
+	DB *gorm.DB
+}
+
+// Transaction runs fn in a transaction that is rolled back when fn returns
+// an error. Repositories that open their own transaction get a savepoint.
+func (s *Store) Transaction(fn func(repos Repositories) error) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			UserDB:      NewUser(tx),
+			ProductDB:   NewProduct(tx),
+			CategoryDB:  NewCategory(tx),
+			WarehouseDB: NewWarehouse(tx),
+			VariantDB:   NewVariant(tx),
+			ImageDB:     NewProductImage(tx),
+			CartDB:      NewCart(tx),
+			AuditDB:     NewAudit(tx),
+		})
+	})
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{DB: db}
+}
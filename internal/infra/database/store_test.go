@@ -0,0 +1,43 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestStore_Transaction(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.Warehouse{}, &entity.AuditEntry{})
+	store := NewStore(db)
+
+	sp, _ := entity.NewWarehouse("SP-01", "São Paulo")
+	entry := entity.NewAuditEntry(nil, entity.AuditActionCreate, "warehouse", sp.ID.String(), nil)
+	err = store.Transaction(func(repos Repositories) error {
+		if err := repos.WarehouseDB.Create(sp); err != nil {
+			return err
+		}
+		return repos.AuditDB.Record(entry)
+	})
+	assert.Nil(t, err)
+
+	rj, _ := entity.NewWarehouse("RJ-01", "Rio de Janeiro")
+	err = store.Transaction(func(repos Repositories) error {
+		if err := repos.WarehouseDB.Create(rj); err != nil {
+			return err
+		}
+		return repos.AuditDB.Record(entry)
+	})
+	assert.NotNil(t, err, "a failed audit entry rolls back the write")
+
+	warehouses, _ := NewWarehouse(db).FindAll()
+	assert.Len(t, warehouses, 1)
+	_, total, _ := NewAudit(db).FindAll(AuditQuery{})
+	assert.Equal(t, int64(1), total)
+}
@@ -10,7 +10,11 @@ type User struct {
 }
 
 func (u *User) Create(user *entity.User) error {
-	return u.DB.Create(user).Error
+	err := u.DB.Create(user).Error
+	if isUniqueViolation(err) {
+		return entity.ErrEmailAlreadyExists
+	}
+	return err
 }
 
 func (u *User) FindByEmail(email string) (*entity.User, error) {
@@ -22,6 +26,26 @@ func (u *User) FindByEmail(email string) (*entity.User, error) {
 	return &user, nil
 }
 
+func (u *User) FindByID(id string) (*entity.User, error) {
+	var user entity.User
+	err := u.DB.Where("id = ?", id).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (u *User) UpdateRole(id, role string) error {
+	result := u.DB.Model(&entity.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func NewUser(db *gorm.DB) *User {
 	return &User{DB: db}
 }
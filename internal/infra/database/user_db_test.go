@@ -33,6 +33,25 @@ func TestCreateUser(t *testing.T) {
 	assert.NotEqual(t, "123456", userFound.Password)
 }
 
+func TestCreateUser_DuplicateEmail(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	db.AutoMigrate(&entity.User{})
+	db.Exec("CREATE UNIQUE INDEX idx_users_email ON users (email)")
+
+	userDB := NewUser(db)
+	user, _ := entity.NewUser("Felipe Dias", "felipe@gmail.com", "123456")
+	err = userDB.Create(user)
+	assert.Nil(t, err)
+
+	user, _ = entity.NewUser("Felipe", "felipe@gmail.com", "654321")
+	err = userDB.Create(user)
+	assert.Equal(t, entity.ErrEmailAlreadyExists, err)
+}
+
 func TestUser_FindByEmail(t *testing.T) {
 	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
 	if err != nil {
@@ -55,3 +74,43 @@ func TestUser_FindByEmail(t *testing.T) {
 	assert.Equal(t, user.Email, userFound.Email)
 	assert.NotEmpty(t, userFound.Password)
 }
+
+func TestUser_FindByID(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	db.AutoMigrate(&entity.User{})
+
+	user, _ := entity.NewUser("Felipe Dias", "felipe@gmail.com", "123456")
+	userDB := NewUser(db)
+	userDB.Create(user)
+
+	userFound, err := userDB.FindByID(user.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, user.Email, userFound.Email)
+	assert.Equal(t, entity.RoleViewer, userFound.Role)
+}
+
+func TestUser_UpdateRole(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+
+	db.AutoMigrate(&entity.User{})
+
+	user, _ := entity.NewUser("Felipe Dias", "felipe@gmail.com", "123456")
+	userDB := NewUser(db)
+	userDB.Create(user)
+
+	err = userDB.UpdateRole(user.ID.String(), entity.RoleEditor)
+	assert.Nil(t, err)
+
+	userFound, _ := userDB.FindByID(user.ID.String())
+	assert.Equal(t, entity.RoleEditor, userFound.Role)
+
+	err = userDB.UpdateRole("4a1a3c5e-0000-0000-0000-000000000000", entity.RoleEditor)
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"gorm.io/gorm"
+)
+
+// productVariant is the stored form of entity.Variant. The price override
+// is kept in nullable columns since GORM cannot leave an embedded pointer
+// nil.
+type productVariant struct {
+	ID            string `gorm:"primaryKey"`
+	ProductID     string
+	SKU           string            `gorm:"column:sku"`
+	Options       map[string]string `gorm:"serializer:json"`
+	PriceAmount   *int64
+	PriceCurrency *string
+	CreatedAt     time.Time
+}
+
+func (productVariant) TableName() string {
+	return "product_variants"
+}
+
+func newProductVariant(variant *entity.Variant) productVariant {
+	row := productVariant{
+		ID:        variant.ID.String(),
+		ProductID: variant.ProductID.String(),
+		SKU:       variant.SKU,
+		Options:   variant.Options,
+		CreatedAt: variant.CreatedAt,
+	}
+	if variant.Price != nil {
+		row.PriceAmount = &variant.Price.Amount
+		row.PriceCurrency = &variant.Price.Currency
+	}
+	return row
+}
+
+func (row productVariant) variant() (entity.Variant, error) {
+	variant := entity.Variant{
+		SKU:       row.SKU,
+		Options:   row.Options,
+		CreatedAt: row.CreatedAt,
+	}
+	var err error
+	if variant.ID, err = pkgEntity.ParseID(row.ID); err != nil {
+		return variant, err
+	}
+	if variant.ProductID, err = pkgEntity.ParseID(row.ProductID); err != nil {
+		return variant, err
+	}
+	if row.PriceAmount != nil && row.PriceCurrency != nil {
+		variant.Price = &pkgEntity.Money{Amount: *row.PriceAmount, Currency: *row.PriceCurrency}
+	}
+	return variant, nil
+}
+
+type Variant struct {
+	DB *gorm.DB
+}
+
+func (v *Variant) Create(variant *entity.Variant) error {
+	return v.DB.Transaction(func(tx *gorm.DB) error {
+		if err := checkVariantOptions(tx, variant); err != nil {
+			return err
+		}
+		row := newProductVariant(variant)
+		err := tx.Create(&row).Error
+		if isUniqueViolation(err) {
+			return entity.ErrSKUExists
+		}
+		return err
+	})
+}
+
+// FindByProduct lists the variants of a product ordered by SKU.
+func (v *Variant) FindByProduct(productID string) ([]entity.Variant, error) {
+	var rows []productVariant
+	err := v.DB.Where("product_id = ?", productID).Order("sku").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	variants := make([]entity.Variant, len(rows))
+	for i, row := range rows {
+		if variants[i], err = row.variant(); err != nil {
+			return nil, err
+		}
+	}
+	return variants, nil
+}
+
+// FindByID finds a variant of the given product; variants of other
+// products are not found.
+func (v *Variant) FindByID(productID, id string) (*entity.Variant, error) {
+	var row productVariant
+	err := v.DB.Where("id = ? AND product_id = ?", id, productID).First(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	variant, err := row.variant()
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (v *Variant) Update(variant *entity.Variant) error {
+	return v.DB.Transaction(func(tx *gorm.DB) error {
+		row := newProductVariant(variant)
+		// An update that changes nothing affects no rows on some drivers, so
+		// existence is checked up front instead of through RowsAffected.
+		var count int64
+		err := tx.Model(&productVariant{}).Where("id = ? AND product_id = ?", row.ID, row.ProductID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := checkVariantOptions(tx, variant); err != nil {
+			return err
+		}
+		err = tx.Model(&productVariant{}).Where("id = ? AND product_id = ?", row.ID, row.ProductID).
+			Select("sku", "options", "price_amount", "price_currency").Updates(&row).Error
+		if isUniqueViolation(err) {
+			return entity.ErrSKUExists
+		}
+		return err
+	})
+}
+
+func (v *Variant) Delete(productID, id string) error {
+	result := v.DB.Where("id = ? AND product_id = ?", id, productID).Delete(&productVariant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// checkVariantOptions rejects a variant whose options another variant of
+// the same product already has, since clients could not tell them apart.
+func checkVariantOptions(tx *gorm.DB, variant *entity.Variant) error {
+	var siblings []productVariant
+	err := tx.Where("product_id = ? AND id <> ?", variant.ProductID.String(), variant.ID.String()).
+		Find(&siblings).Error
+	if err != nil {
+		return err
+	}
+	for _, sibling := range siblings {
+		other := entity.Variant{Options: sibling.Options}
+		if other.HasOptions(variant.Options) {
+			return entity.ErrVariantOptionsExist
+		}
+	}
+	return nil
+}
+
+func NewVariant(db *gorm.DB) *Variant {
+	return &Variant{DB: db}
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestVariant_CreateAndFind(t *testing.T) {
+	db := newProductTestDB(t)
+	variantDB := NewVariant(db)
+
+	productID := pkgEntity.NewID()
+	price := pkgEntity.Money{Amount: 1990, Currency: "BRL"}
+	large, _ := entity.NewVariant(productID, "SHIRT-L", map[string]string{"size": "L"}, &price)
+	medium, _ := entity.NewVariant(productID, "SHIRT-M", map[string]string{"size": "M"}, nil)
+	assert.Nil(t, variantDB.Create(large))
+	assert.Nil(t, variantDB.Create(medium))
+
+	sameSKU, _ := entity.NewVariant(pkgEntity.NewID(), "shirt-m", map[string]string{"size": "M"}, nil)
+	assert.Equal(t, entity.ErrSKUExists, variantDB.Create(sameSKU))
+	sameOptions, _ := entity.NewVariant(productID, "SHIRT-M2", map[string]string{"size": "M"}, nil)
+	assert.Equal(t, entity.ErrVariantOptionsExist, variantDB.Create(sameOptions))
+
+	variants, err := variantDB.FindByProduct(productID.String())
+	assert.Nil(t, err)
+	assert.Len(t, variants, 2)
+	assert.Equal(t, "SHIRT-L", variants[0].SKU)
+	assert.Equal(t, price, *variants[0].Price)
+	assert.Equal(t, map[string]string{"size": "M"}, variants[1].Options)
+	assert.Nil(t, variants[1].Price)
+
+	_, err = variantDB.FindByID(pkgEntity.NewID().String(), medium.ID.String())
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
+
+func TestVariant_Update(t *testing.T) {
+	db := newProductTestDB(t)
+	variantDB := NewVariant(db)
+
+	productID := pkgEntity.NewID()
+	price := pkgEntity.Money{Amount: 1990, Currency: "BRL"}
+	large, _ := entity.NewVariant(productID, "SHIRT-L", map[string]string{"size": "L"}, &price)
+	medium, _ := entity.NewVariant(productID, "SHIRT-M", map[string]string{"size": "M"}, nil)
+	variantDB.Create(large)
+	variantDB.Create(medium)
+
+	large.Options = map[string]string{"size": "M"}
+	assert.Equal(t, entity.ErrVariantOptionsExist, variantDB.Update(large))
+	large.Options = map[string]string{"size": "L"}
+	large.SKU = "SHIRT-M"
+	assert.Equal(t, entity.ErrSKUExists, variantDB.Update(large))
+
+	large.SKU = "SHIRT-XL"
+	large.Options = map[string]string{"size": "XL"}
+	large.Price = nil
+	assert.Nil(t, variantDB.Update(large))
+	found, err := variantDB.FindByID(productID.String(), large.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, "SHIRT-XL", found.SKU)
+	assert.Equal(t, map[string]string{"size": "XL"}, found.Options)
+	assert.Nil(t, found.Price)
+	assert.Nil(t, variantDB.Update(large), "an unchanged variant still updates")
+
+	other, _ := entity.NewVariant(pkgEntity.NewID(), "OTHER", map[string]string{"size": "S"}, nil)
+	assert.Equal(t, gorm.ErrRecordNotFound, variantDB.Update(other))
+}
+
+func TestVariant_Delete(t *testing.T) {
+	db := newProductTestDB(t)
+	variantDB := NewVariant(db)
+
+	productID := pkgEntity.NewID()
+	variant, _ := entity.NewVariant(productID, "SHIRT-M", map[string]string{"size": "M"}, nil)
+	variantDB.Create(variant)
+
+	assert.Equal(t, gorm.ErrRecordNotFound, variantDB.Delete(pkgEntity.NewID().String(), variant.ID.String()))
+	assert.Nil(t, variantDB.Delete(productID.String(), variant.ID.String()))
+	_, err := variantDB.FindByID(productID.String(), variant.ID.String())
+	assert.Equal(t, gorm.ErrRecordNotFound, err)
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"gorm.io/gorm"
+)
+
+type Warehouse struct {
+	DB *gorm.DB
+}
+
+func (w *Warehouse) Create(warehouse *entity.Warehouse) error {
+	err := w.DB.Create(warehouse).Error
+	if isUniqueViolation(err) {
+		return entity.ErrWarehouseCodeExists
+	}
+	return err
+}
+
+func (w *Warehouse) FindAll() ([]entity.Warehouse, error) {
+	var warehouses []entity.Warehouse
+	err := w.DB.Order("code").Find(&warehouses).Error
+	return warehouses, err
+}
+
+func (w *Warehouse) FindByID(id string) (*entity.Warehouse, error) {
+	var warehouse entity.Warehouse
+	err := w.DB.Where("id = ?", id).First(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (w *Warehouse) Update(warehouse *entity.Warehouse) error {
+	result := w.DB.Model(&entity.Warehouse{}).Where("id = ?", warehouse.ID).
+		Updates(map[string]interface{}{"code": warehouse.Code, "name": warehouse.Name})
+	if isUniqueViolation(result.Error) {
+		return entity.ErrWarehouseCodeExists
+	}
+	return result.Error
+}
+
+func NewWarehouse(db *gorm.DB) *Warehouse {
+	return &Warehouse{DB: db}
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestWarehouse_Create(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Error(err)
+	}
+	db.AutoMigrate(&entity.Warehouse{})
+	db.Exec("CREATE UNIQUE INDEX idx_warehouses_code ON warehouses (code)")
+	warehouseDB := NewWarehouse(db)
+
+	rj, _ := entity.NewWarehouse("RJ-01", "Rio de Janeiro")
+	sp, _ := entity.NewWarehouse("SP-01", "São Paulo")
+	assert.Nil(t, warehouseDB.Create(sp))
+	assert.Nil(t, warehouseDB.Create(rj))
+	duplicate, _ := entity.NewWarehouse("sp-01", "Outro")
+	assert.Equal(t, entity.ErrWarehouseCodeExists, warehouseDB.Create(duplicate))
+
+	rj.Code = "SP-01"
+	assert.Equal(t, entity.ErrWarehouseCodeExists, warehouseDB.Update(rj))
+	rj.Code, rj.Name = "RJ-02", "Rio"
+	assert.Nil(t, warehouseDB.Update(rj))
+
+	warehouses, err := warehouseDB.FindAll()
+	assert.Nil(t, err)
+	assert.Len(t, warehouses, 2)
+	assert.Equal(t, "RJ-02", warehouses[0].Code)
+	assert.Equal(t, "Rio", warehouses[0].Name)
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	local := NewLocal(dir, "http://localhost:8000/images/")
+
+	ctx := context.Background()
+	assert.Nil(t, local.Put(ctx, "products/1/a.png", "image/png", []byte("png")))
+	data, err := os.ReadFile(filepath.Join(dir, "products", "1", "a.png"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("png"), data)
+	assert.Equal(t, "http://localhost:8000/images/products/1/a.png", local.URL("products/1/a.png"))
+
+	assert.Nil(t, local.Delete(ctx, "products/1/a.png"))
+	_, err = os.Stat(filepath.Join(dir, "products", "1", "a.png"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, local.Delete(ctx, "products/1/a.png"))
+
+	for _, key := range []string{"", "/etc/passwd", "../a.png", "products/../../a.png", `products\a.png`} {
+		assert.Equal(t, ErrInvalidKey, local.Put(ctx, key, "image/png", nil), key)
+	}
+}
@@ -0,0 +1,32 @@
+// Package storage keeps uploaded files, such as product images, outside the
+// database.
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage stores files under slash-separated keys like
+// "products/<id>/<image>.jpg" and knows the public URL of each one.
+type Storage interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// validKey rejects keys that could escape the storage root.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/go-chi/chi/middleware"
+)
+
+const (
+	auditEntityProduct   = "product"
+	auditEntityUser      = "user"
+	auditEntityCategory  = "category"
+	auditEntityWarehouse = "warehouse"
+	auditEntityVariant   = "variant"
+)
+
+// recordAudit appends an entry for a write. It is meant to run in the
+// write's transaction, through Store, so that neither is kept without the
+// other.
+func recordAudit(db database.AuditInterface, r *http.Request, action, entityType, entityID string, before, after interface{}) error {
+	changes, err := entity.DiffJSON(before, after)
+	if err != nil {
+		return err
+	}
+	entry := entity.NewAuditEntry(actorID(r), action, entityType, entityID, changes)
+	entry.RequestID = middleware.GetReqID(r.Context())
+	entry.ClientIP = clientIP(r)
+	return db.Record(entry)
+}
+
+// clientIP relies on middlewares.RealIP having copied the address reported
+// by a trusted proxy into RemoteAddr, if any.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
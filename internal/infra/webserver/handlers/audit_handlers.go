@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+)
+
+type AuditHandler struct {
+	AuditDB database.AuditInterface
+}
+
+func NewAuditHandler(db database.AuditInterface) *AuditHandler {
+	return &AuditHandler{
+		AuditDB: db,
+	}
+}
+
+// Get audit log godoc
+// @Summary 		List audit log
+// @Description List recorded writes, newest first. Admin only.
+// @Tags 				audit
+// @Accept  		json
+// @Produce  		json
+// @Param 			page					query			string	false	"page number"
+// @Param 			limit					query			string	false	"limit"
+// @Param 			actor					query			string	false	"ID of the user who made the change" Format(uuid)
+// @Param 			entity_type		query			string	false	"kind of entity changed" Enums(product, user, category, warehouse, variant)
+// @Param 			entity_id			query			string	false	"ID of the entity changed"
+// @Param 			from					query			string	false	"recorded at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param 			to						query			string	false	"recorded before (RFC 3339 or YYYY-MM-DD)"
+// @Success 		200 	{object}	dto.AuditListOutput
+// @Failure 		400 	{object}	Problem
+// @Failure 		403 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/audit [get]
+// @Security 		ApiKeyAuth
+func (h *AuditHandler) GetAudit(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query := database.AuditQuery{
+		ActorID:    values.Get("actor"),
+		EntityType: values.Get("entity_type"),
+		EntityID:   values.Get("entity_id"),
+	}
+	query.Page, _ = strconv.Atoi(values.Get("page"))
+	query.Limit, _ = strconv.Atoi(values.Get("limit"))
+	var err error
+	if query.From, err = timeParam(values.Get("from"), "from"); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	if query.To, err = timeParam(values.Get("to"), "to"); err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	entries, total, err := h.AuditDB.FindAll(query)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if entries == nil {
+		entries = []entity.AuditEntry{}
+	}
+
+	query = query.Normalize()
+	links := newPageLinks(r.URL, query.Page, query.Limit, total)
+	output := dto.AuditListOutput{
+		Items:      entries,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      total,
+		TotalPages: links.TotalPages,
+		Next:       links.Next,
+		Prev:       links.Prev,
+	}
+	links.writeHeaders(w, total)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
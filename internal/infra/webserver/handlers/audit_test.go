@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditStoreStub struct {
+	entries []*entity.AuditEntry
+}
+
+func (s *auditStoreStub) Record(entry *entity.AuditEntry) error {
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *auditStoreStub) FindAll(query database.AuditQuery) ([]entity.AuditEntry, int64, error) {
+	return nil, 0, nil
+}
+
+func TestRecordAudit(t *testing.T) {
+	store := &auditStoreStub{}
+	r := httptest.NewRequest("PUT", "/products/1", nil)
+	r.RemoteAddr = "203.0.113.7:51234"
+	before, _ := entity.NewProduct("Product 1", brl(1000))
+	after := *before
+	after.Name = "Product 2"
+
+	err := recordAudit(store, r, entity.AuditActionUpdate, auditEntityProduct, before.ID.String(), before, &after)
+
+	assert.Nil(t, err)
+	assert.Len(t, store.entries, 1)
+	entry := store.entries[0]
+	assert.Nil(t, entry.ActorID)
+	assert.Equal(t, "203.0.113.7", entry.ClientIP)
+	assert.Equal(t, entity.AuditChange{From: "Product 1", To: "Product 2"}, entry.Changes["name"])
+	assert.Len(t, entry.Changes, 1)
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/go-chi/chi"
+	"gorm.io/gorm"
+)
+
+type CartHandler struct {
+	CartDB    database.CartInterface
+	ProductDB database.ProductInterface
+}
+
+func NewCartHandler(db database.CartInterface, productDB database.ProductInterface) *CartHandler {
+	return &CartHandler{
+		CartDB:    db,
+		ProductDB: productDB,
+	}
+}
+
+// Get cart godoc
+// @Summary 		Get cart
+// @Description Get the cart of the current user. Items are revalidated first: deleted products become unavailable and price changes are applied and flagged.
+// @Tags 				cart
+// @Accept  		json
+// @Produce  		json
+// @Success 		200 	{object}	entity.Cart
+// @Failure 		401 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/cart [get]
+// @Security 		ApiKeyAuth
+func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cartOwner(w, r)
+	if !ok {
+		return
+	}
+	items, err := h.revalidatedItems(userID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	writeCart(w, userID, items)
+}
+
+// Add cart item godoc
+// @Summary 		Add cart item
+// @Description Add a product to the cart of the current user at its current price. Adding a product already in the cart adds to its quantity.
+// @Tags 				cart
+// @Accept  		json
+// @Produce  		json
+// @Param 			request		body			dto.CartItemInput true "item"
+// @Success 		200 			{object}	entity.Cart
+// @Failure 		400 			{object}	Problem
+// @Failure 		401 			{object}	Problem
+// @Failure 		409 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/cart/items [post]
+// @Security 		ApiKeyAuth
+func (h *CartHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cartOwner(w, r)
+	if !ok {
+		return
+	}
+	var input dto.CartItemInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	product, err := h.ProductDB.FindByID(input.ProductID)
+	if err == gorm.ErrRecordNotFound {
+		err = entity.ErrProductUnavailable
+	}
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	item, err := entity.NewCartItem(product, input.Quantity)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if err := h.CartDB.AddItem(userID.String(), item); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	items, err := h.revalidatedItems(userID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	writeCart(w, userID, items)
+}
+
+// Update cart item godoc
+// @Summary 		Update cart item
+// @Description Set the quantity of a product in the cart of the current user, which also acknowledges a price change
+// @Tags 				cart
+// @Accept  		json
+// @Produce  		json
+// @Param 			productID		path			string	true	"product ID" Format(uuid)
+// @Param 			request			body			dto.CartItemQuantityInput true "quantity"
+// @Success 		200 				{object}	entity.Cart
+// @Failure 		400 				{object}	Problem
+// @Failure 		401 				{object}	Problem
+// @Failure 		404 				{object}	Problem
+// @Failure 		422 				{object}	Problem
+// @Failure 		500 				{object}	Problem
+// @Router 			/cart/items/{productID} [put]
+// @Security 		ApiKeyAuth
+func (h *CartHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cartOwner(w, r)
+	if !ok {
+		return
+	}
+	var input dto.CartItemQuantityInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	productID, err := pkgEntity.ParseID(chi.URLParam(r, "productID"))
+	if err != nil {
+		WriteError(w, r, entity.ErrInvalidID)
+		return
+	}
+	items, err := h.revalidatedItems(userID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	i := findCartItem(items, productID)
+	if i < 0 {
+		WriteError(w, r, gorm.ErrRecordNotFound)
+		return
+	}
+	if items[i].Status == entity.CartItemUnavailable {
+		WriteError(w, r, entity.ErrProductUnavailable)
+		return
+	}
+	if err := items[i].SetQuantity(input.Quantity); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if err := h.CartDB.SaveItems(userID.String(), items[i:i+1]); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	writeCart(w, userID, items)
+}
+
+// Remove cart item godoc
+// @Summary 		Remove cart item
+// @Description Remove a product from the cart of the current user
+// @Tags 				cart
+// @Accept  		json
+// @Produce  		json
+// @Param 			productID		path			string	true	"product ID" Format(uuid)
+// @Success 		200 				{object}	entity.Cart
+// @Failure 		401 				{object}	Problem
+// @Failure 		404 				{object}	Problem
+// @Failure 		500 				{object}	Problem
+// @Router 			/cart/items/{productID} [delete]
+// @Security 		ApiKeyAuth
+func (h *CartHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cartOwner(w, r)
+	if !ok {
+		return
+	}
+	err := h.CartDB.RemoveItem(userID.String(), chi.URLParam(r, "productID"))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	items, err := h.revalidatedItems(userID)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	writeCart(w, userID, items)
+}
+
+// Clear cart godoc
+// @Summary 		Clear cart
+// @Description Remove every item from the cart of the current user
+// @Tags 				cart
+// @Accept  		json
+// @Produce  		json
+// @Success 		200 	{object}	entity.Cart
+// @Failure 		401 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/cart [delete]
+// @Security 		ApiKeyAuth
+func (h *CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
+	userID, ok := cartOwner(w, r)
+	if !ok {
+		return
+	}
+	if err := h.CartDB.Clear(userID.String()); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	writeCart(w, userID, nil)
+}
+
+// revalidatedItems loads the cart items of userID and brings them up to
+// date with their products, saving the items that changed.
+func (h *CartHandler) revalidatedItems(userID pkgEntity.ID) ([]entity.CartItem, error) {
+	items, err := h.CartDB.FindItems(userID.String())
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ProductID.String()
+	}
+	products, err := h.ProductDB.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[pkgEntity.ID]*entity.Product{}
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+	var changed []entity.CartItem
+	for i := range items {
+		if items[i].Revalidate(byID[items[i].ProductID]) {
+			changed = append(changed, items[i])
+		}
+	}
+	if len(changed) > 0 {
+		if err := h.CartDB.SaveItems(userID.String(), changed); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// cartOwner returns the user whose cart the request works on, writing the
+// error response when the token subject is not a user ID.
+func cartOwner(w http.ResponseWriter, r *http.Request) (pkgEntity.ID, bool) {
+	userID, _ := currentUser(r)
+	id, err := pkgEntity.ParseID(userID)
+	if err != nil {
+		WriteProblem(w, r, http.StatusUnauthorized, "unauthorized", "invalid token subject")
+		return pkgEntity.ID{}, false
+	}
+	return id, true
+}
+
+func findCartItem(items []entity.CartItem, productID pkgEntity.ID) int {
+	for i, item := range items {
+		if item.ProductID == productID {
+			return i
+		}
+	}
+	return -1
+}
+
+func writeCart(w http.ResponseWriter, userID pkgEntity.ID, items []entity.CartItem) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entity.NewCart(userID, items))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database/migrations"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCartHandler(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatal(err)
+	}
+	productDB := database.NewProduct(db)
+	handler := NewCartHandler(database.NewCart(db), productDB)
+	shirt, _ := entity.NewProduct("Shirt", brl(1000))
+	mug, _ := entity.NewProduct("Mug", brl(500))
+	productDB.Create(shirt)
+	productDB.Create(mug)
+	userID := pkgEntity.NewID().String()
+
+	call := func(handle http.HandlerFunc, method, body string, params map[string]string) (int, entity.Cart) {
+		r := withUser(httptest.NewRequest(method, "/cart", strings.NewReader(body)), userID, entity.RoleViewer, params)
+		w := httptest.NewRecorder()
+		handle(w, r)
+		var cart entity.Cart
+		json.NewDecoder(w.Body).Decode(&cart)
+		return w.Code, cart
+	}
+
+	status, cart := call(handler.AddItem, "POST", `{"product_id": "`+shirt.ID.String()+`", "quantity": 2}`, nil)
+	assert.Equal(t, http.StatusOK, status)
+	status, cart = call(handler.AddItem, "POST", `{"product_id": "`+shirt.ID.String()+`", "quantity": 1}`, nil)
+	assert.Equal(t, http.StatusOK, status)
+	status, cart = call(handler.AddItem, "POST", `{"product_id": "`+mug.ID.String()+`", "quantity": 1}`, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, cart.Items, 2)
+	assert.Equal(t, int64(3), cart.Items[0].Quantity)
+	assert.Equal(t, []pkgEntity.Money{brl(3500)}, cart.Totals)
+
+	status, _ = call(handler.AddItem, "POST", `{"product_id": "`+pkgEntity.NewID().String()+`", "quantity": 1}`, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+	shirt.Price = brl(1200)
+	productDB.Update(shirt, "")
+	productDB.Delete(mug.ID.String(), mug.Version)
+	_, cart = call(handler.GetCart, "GET", "", nil)
+	assert.Equal(t, entity.CartItemPriceChanged, cart.Items[0].Status)
+	assert.Equal(t, brl(1000), *cart.Items[0].PreviousPrice)
+	assert.Equal(t, entity.CartItemUnavailable, cart.Items[1].Status)
+	assert.Equal(t, int64(3), cart.ItemCount)
+	assert.Equal(t, []pkgEntity.Money{brl(3600)}, cart.Totals)
+
+	status, _ = call(handler.UpdateItem, "PUT", `{"quantity": 2}`, map[string]string{"productID": mug.ID.String()})
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	status, cart = call(handler.UpdateItem, "PUT", `{"quantity": 1}`, map[string]string{"productID": shirt.ID.String()})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, entity.CartItemAvailable, cart.Items[0].Status)
+	assert.Equal(t, []pkgEntity.Money{brl(1200)}, cart.Totals)
+
+	status, cart = call(handler.RemoveItem, "DELETE", "", map[string]string{"productID": mug.ID.String()})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, cart.Items, 1)
+	status, _ = call(handler.RemoveItem, "DELETE", "", map[string]string{"productID": mug.ID.String()})
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, cart = call(handler.ClearCart, "DELETE", "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, cart.Items)
+}
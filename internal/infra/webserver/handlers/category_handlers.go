@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/go-chi/chi"
+)
+
+type CategoryHandler struct {
+	CategoryDB database.CategoryInterface
+	ProductDB  database.ProductInterface
+	Store      database.StoreInterface
+}
+
+func NewCategoryHandler(db database.CategoryInterface, productDB database.ProductInterface, store database.StoreInterface) *CategoryHandler {
+	return &CategoryHandler{
+		CategoryDB: db,
+		ProductDB:  productDB,
+		Store:      store,
+	}
+}
+
+// Get categories godoc
+// @Summary 		List categories
+// @Description List every category as a tree, siblings ordered by name
+// @Tags 				categories
+// @Accept  		json
+// @Produce  		json
+// @Success 		200 	{array}		dto.CategoryNode
+// @Failure 		500 	{object}	Problem
+// @Router 			/categories [get]
+// @Security 		ApiKeyAuth
+func (h *CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.CategoryDB.FindAll()
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.NewCategoryTree(categories))
+}
+
+// Get category godoc
+// @Summary 		Get category
+// @Description Get category
+// @Tags 				categories
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"category ID" Format(uuid)
+// @Success 		200 	{object}	entity.Category
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/categories/{id} [get]
+// @Security 		ApiKeyAuth
+func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := h.CategoryDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(category)
+}
+
+// Create category godoc
+// @Summary 		Create category
+// @Description Create a category, optionally below an existing parent
+// @Tags 				categories
+// @Accept  		json
+// @Produce  		json
+// @Param 			request		body			dto.CategoryInput true "category request"
+// @Success 		201 			{object}	entity.Category
+// @Failure 		400 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/categories [post]
+// @Security 		ApiKeyAuth
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var input dto.CategoryInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	category, err := entity.NewCategory(input.Name, parentID(input))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.CategoryDB.Create(category); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionCreate, auditEntityCategory, category.ID.String(), nil, category)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
+// Update category godoc
+// @Summary 		Update category
+// @Description Rename a category or move it below another parent. A category cannot be moved below itself or one of its subcategories.
+// @Tags 				categories
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"category ID" Format(uuid)
+// @Param 			request		body			dto.CategoryInput true "category request"
+// @Success 		200 			{object}	entity.Category
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/categories/{id} [put]
+// @Security 		ApiKeyAuth
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var input dto.CategoryInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	before, err := h.CategoryDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	category := *before
+	category.Name = input.Name
+	category.ParentID = parentID(input)
+	if err := category.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.CategoryDB.Update(&category); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityCategory, id, before, &category)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(category)
+}
+
+// Delete category godoc
+// @Summary 		Delete category
+// @Description Delete a category without subcategories. Its products are kept and only lose the link to it.
+// @Tags 				categories
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"category ID" Format(uuid)
+// @Success 		200
+// @Failure 		404 	{object}	Problem
+// @Failure 		409 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/categories/{id} [delete]
+// @Security 		ApiKeyAuth
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	before, err := h.CategoryDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.CategoryDB.Delete(id); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionDelete, auditEntityCategory, id, before, nil)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+}
+
+// Get product categories godoc
+// @Summary 		Get product categories
+// @Description List the categories a product belongs to
+// @Tags 				products
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Success 		200 	{array}		entity.Category
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/categories [get]
+// @Security 		ApiKeyAuth
+func (h *CategoryHandler) GetProductCategories(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	_, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	categories, err := h.CategoryDB.FindByProduct(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	writeCategories(w, categories)
+}
+
+// Set product categories godoc
+// @Summary 		Set product categories
+// @Description Replace the categories a product belongs to
+// @Tags 				products
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			request		body			dto.SetProductCategoriesInput true "category IDs"
+// @Success 		200 			{array}		entity.Category
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id}/categories [put]
+// @Security 		ApiKeyAuth
+func (h *CategoryHandler) SetProductCategories(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var input dto.SetProductCategoriesInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	_, err = h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	before, err := h.CategoryDB.FindByProduct(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	var after []entity.Category
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.CategoryDB.SetProductCategories(id, input.CategoryIDs); err != nil {
+			return err
+		}
+		after, err = tx.CategoryDB.FindByProduct(id)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityProduct, id,
+			map[string]interface{}{"category_ids": categoryIDs(before)},
+			map[string]interface{}{"category_ids": categoryIDs(after)})
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	writeCategories(w, after)
+}
+
+func writeCategories(w http.ResponseWriter, categories []entity.Category) {
+	if categories == nil {
+		categories = []entity.Category{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(categories)
+}
+
+func parentID(input dto.CategoryInput) *pkgEntity.ID {
+	if input.ParentID == nil {
+		return nil
+	}
+	id, _ := pkgEntity.ParseID(*input.ParentID)
+	return &id
+}
+
+func categoryIDs(categories []entity.Category) []string {
+	ids := make([]string, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID.String()
+	}
+	return ids
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/jwtauth"
+)
+
+// currentUser returns the subject and role of the verified JWT in the
+// request context, or empty strings when there is none.
+func currentUser(r *http.Request) (id, role string) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return "", ""
+	}
+	id, _ = claims["sub"].(string)
+	role, _ = claims["role"].(string)
+	return id, role
+}
+
+// ownedProduct loads the product of the request, writing the error response
+// when it does not exist or the current user may not change it.
+func ownedProduct(w http.ResponseWriter, r *http.Request, productDB database.ProductInterface) (*entity.Product, bool) {
+	product, err := productDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, r, err)
+		return nil, false
+	}
+	if !authorizeOwner(w, r, product, "change") {
+		return nil, false
+	}
+	return product, true
+}
+
+// authorizeOwner reports whether the current user owns product or is an
+// admin, writing a 403 naming action when neither holds.
+func authorizeOwner(w http.ResponseWriter, r *http.Request, product *entity.Product, action string) bool {
+	userID, role := currentUser(r)
+	if role != entity.RoleAdmin && !product.IsOwnedBy(userID) {
+		WriteProblem(w, r, http.StatusForbidden, "forbidden", "only the owner can "+action+" this product")
+		return false
+	}
+	return true
+}
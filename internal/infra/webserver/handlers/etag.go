@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errIfMatchRequired = errors.New("If-Match header is required")
+	errIfMatchInvalid  = errors.New("If-Match does not match the current version")
+	errIfMatchWeak     = errors.New("If-Match uses strong comparison, weak entity tags never match")
+)
+
+func versionETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ifMatch holds the versions listed in an If-Match header; any is set for
+// "If-Match: *", which matches whatever version is currently stored.
+type ifMatch struct {
+	any      bool
+	versions []int
+}
+
+// version returns current when the header matches it.
+func (m ifMatch) version(current int) (int, error) {
+	if m.any {
+		return current, nil
+	}
+	for _, version := range m.versions {
+		if version == current {
+			return current, nil
+		}
+	}
+	return 0, errIfMatchInvalid
+}
+
+// parseIfMatch reads the versions a client expects to modify from the
+// If-Match header, a comma-separated list of entity tags or "*". If-Match
+// compares strongly, so a weak tag is refused instead of being treated as
+// a match; tags that are not versionETag never match.
+func parseIfMatch(r *http.Request) (ifMatch, error) {
+	value := strings.TrimSpace(strings.Join(r.Header.Values("If-Match"), ","))
+	if value == "" {
+		return ifMatch{}, errIfMatchRequired
+	}
+	if value == "*" {
+		return ifMatch{any: true}, nil
+	}
+	var match ifMatch
+	for {
+		value = strings.TrimLeft(value, ", \t")
+		if value == "" {
+			return match, nil
+		}
+		if strings.HasPrefix(value, "W/") {
+			return ifMatch{}, errIfMatchWeak
+		}
+		if value[0] != '"' {
+			return ifMatch{}, errIfMatchInvalid
+		}
+		end := strings.IndexByte(value[1:], '"')
+		if end < 0 {
+			return ifMatch{}, errIfMatchInvalid
+		}
+		if version, err := strconv.Atoi(value[1 : end+1]); err == nil {
+			match.versions = append(match.versions, version)
+		}
+		value = strings.TrimLeft(value[end+2:], " \t")
+		if value != "" && value[0] != ',' {
+			return ifMatch{}, errIfMatchInvalid
+		}
+	}
+}
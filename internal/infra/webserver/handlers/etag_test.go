@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		current int
+		version int
+		err     error
+	}{
+		{versionETag(3), 3, 3, nil},
+		{versionETag(3), 4, 0, errIfMatchInvalid},
+		{`"1", "3"`, 3, 3, nil},
+		{`"1","2",, "abc"`, 3, 0, errIfMatchInvalid},
+		{`W/"3"`, 3, 0, errIfMatchWeak},
+		{`"1", W/"3"`, 3, 0, errIfMatchWeak},
+		{"", 3, 0, errIfMatchRequired},
+		{"*", 3, 3, nil},
+		{`"3`, 3, 0, errIfMatchInvalid},
+		{`"3" "4"`, 3, 0, errIfMatchInvalid},
+		{`3`, 3, 0, errIfMatchInvalid},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPut, "/products/1", nil)
+		if tt.header != "" {
+			req.Header.Set("If-Match", tt.header)
+		}
+		match, err := parseIfMatch(req)
+		version := 0
+		if err == nil {
+			version, err = match.version(tt.current)
+		}
+		assert.Equal(t, tt.err, err, tt.header)
+		assert.Equal(t, tt.version, version, tt.header)
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/products/1", nil)
+	req.Header.Add("If-Match", `"1"`)
+	req.Header.Add("If-Match", `"2"`)
+	match, err := parseIfMatch(req)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2}, match.versions)
+}
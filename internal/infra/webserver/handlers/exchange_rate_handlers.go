@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/go-chi/chi"
+)
+
+type ExchangeRateHandler struct {
+	ExchangeRateDB database.ExchangeRateInterface
+}
+
+func NewExchangeRateHandler(db database.ExchangeRateInterface) *ExchangeRateHandler {
+	return &ExchangeRateHandler{ExchangeRateDB: db}
+}
+
+// Get exchange rates godoc
+// @Summary 		List exchange rates
+// @Description List the stored exchange rates used to display prices in other currencies
+// @Tags 				exchange-rates
+// @Accept  		json
+// @Produce  		json
+// @Success 		200 	{array}		entity.ExchangeRate
+// @Failure 		500 	{object}	Problem
+// @Router 			/exchange-rates [get]
+// @Security 		ApiKeyAuth
+func (h *ExchangeRateHandler) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.ExchangeRateDB.FindAll()
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if rates == nil {
+		rates = []entity.ExchangeRate{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rates)
+}
+
+// Save exchange rate godoc
+// @Summary 		Save exchange rate
+// @Description Create or replace the rate of one unit of base in quote. The inverse rate is derived from it when not stored. Admin only.
+// @Tags 				exchange-rates
+// @Accept  		json
+// @Produce  		json
+// @Param 			base			path			string	true	"ISO 4217 code"
+// @Param 			quote			path			string	true	"ISO 4217 code"
+// @Param 			request		body			dto.SaveExchangeRateInput true "rate"
+// @Success 		200 			{object}	entity.ExchangeRate
+// @Failure 		400 			{object}	Problem
+// @Failure 		403 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/exchange-rates/{base}/{quote} [put]
+// @Security 		ApiKeyAuth
+func (h *ExchangeRateHandler) SaveExchangeRate(w http.ResponseWriter, r *http.Request) {
+	var input dto.SaveExchangeRateInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	base := strings.ToUpper(chi.URLParam(r, "base"))
+	quote := strings.ToUpper(chi.URLParam(r, "quote"))
+	rate, err := entity.NewExchangeRate(base, quote, input.Rate)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.ExchangeRateDB.Save(rate)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rate)
+}
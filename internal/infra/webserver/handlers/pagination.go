@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type pageLinks struct {
+	TotalPages int
+	First      string
+	Last       string
+	Next       string
+	Prev       string
+}
+
+// newPageLinks builds the navigation links for a page of a listing, keeping
+// every other query parameter of the request.
+func newPageLinks(u *url.URL, page, limit int, total int64) pageLinks {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	links := pageLinks{TotalPages: totalPages}
+	pageURL := func(n int) string {
+		values := u.Query()
+		values.Set("page", strconv.Itoa(n))
+		values.Set("limit", strconv.Itoa(limit))
+		return (&url.URL{Path: u.Path, RawQuery: values.Encode()}).String()
+	}
+	links.First = pageURL(1)
+	links.Last = pageURL(totalPages)
+	if totalPages == 0 {
+		links.Last = links.First
+	}
+	if page < totalPages {
+		links.Next = pageURL(page + 1)
+	}
+	if page > 1 {
+		links.Prev = pageURL(page - 1)
+	}
+	return links
+}
+
+// writeHeaders sets the RFC 8288 Link header and X-Total-Count.
+func (l pageLinks) writeHeaders(w http.ResponseWriter, total int64) {
+	var parts []string
+	for _, link := range []struct{ rel, url string }{
+		{"first", l.First}, {"prev", l.Prev}, {"next", l.Next}, {"last", l.Last},
+	} {
+		if link.url != "" {
+			parts = append(parts, fmt.Sprintf(`<%s>; rel="%s"`, link.url, link.rel))
+		}
+	}
+	w.Header().Set("Link", strings.Join(parts, ", "))
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+}
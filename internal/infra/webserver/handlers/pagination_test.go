@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPageLinks(t *testing.T) {
+	u, _ := url.Parse("/products?q=shirt&page=2&limit=10")
+
+	links := newPageLinks(u, 2, 10, 27)
+	assert.Equal(t, 3, links.TotalPages)
+	assert.Equal(t, "/products?limit=10&page=1&q=shirt", links.First)
+	assert.Equal(t, "/products?limit=10&page=1&q=shirt", links.Prev)
+	assert.Equal(t, "/products?limit=10&page=3&q=shirt", links.Next)
+	assert.Equal(t, "/products?limit=10&page=3&q=shirt", links.Last)
+
+	links = newPageLinks(u, 3, 10, 27)
+	assert.Empty(t, links.Next)
+
+	links = newPageLinks(u, 1, 10, 0)
+	assert.Equal(t, 0, links.TotalPages)
+	assert.Empty(t, links.Next)
+	assert.Empty(t, links.Prev)
+	assert.Equal(t, links.First, links.Last)
+}
+
+func TestPageLinks_WriteHeaders(t *testing.T) {
+	u, _ := url.Parse("/products")
+	rec := httptest.NewRecorder()
+
+	newPageLinks(u, 1, 10, 15).writeHeaders(rec, 15)
+	assert.Equal(t, "15", rec.Header().Get("X-Total-Count"))
+	assert.Equal(t, `</products?limit=10&page=1>; rel="first", </products?limit=10&page=2>; rel="next", </products?limit=10&page=2>; rel="last"`, rec.Header().Get("Link"))
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/go-chi/chi/middleware"
+	"gorm.io/gorm"
+)
+
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is a stable,
+// machine-readable identifier clients can switch on; Type is derived from it.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Code      string `json:"code"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors is only set for validation failures.
+	Errors []dto.FieldError `json:"errors,omitempty"`
+}
+
+type problemMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// problemMappings translates domain errors into problems. Errors that are not
+// listed here are reported as internal errors without leaking their message.
+var problemMappings = []problemMapping{
+	{entity.ErrIDIsRequired, http.StatusBadRequest, "id_required"},
+	{entity.ErrInvalidID, http.StatusBadRequest, "invalid_id"},
+	{entity.ErrNameIsRequired, http.StatusBadRequest, "name_required"},
+	{entity.ErrPriceIsRequired, http.StatusBadRequest, "price_required"},
+	{entity.ErrInvalidPrice, http.StatusBadRequest, "invalid_price"},
+	{pkgEntity.ErrInvalidCurrency, http.StatusBadRequest, "invalid_currency"},
+	{pkgEntity.ErrInvalidAmount, http.StatusBadRequest, "invalid_amount"},
+	{entity.ErrInvalidRole, http.StatusBadRequest, "invalid_role"},
+	{entity.ErrInvalidExchangeRate, http.StatusBadRequest, "invalid_exchange_rate"},
+	{entity.ErrInvalidTag, http.StatusBadRequest, "invalid_tag"},
+	{entity.ErrInvalidQuantity, http.StatusBadRequest, "invalid_quantity"},
+	{entity.ErrInvalidStockReason, http.StatusBadRequest, "invalid_stock_reason"},
+	{entity.ErrInvalidReservationTTL, http.StatusBadRequest, "invalid_reservation_ttl"},
+	{entity.ErrInvalidWarehouseCode, http.StatusBadRequest, "invalid_warehouse_code"},
+	{entity.ErrSameWarehouse, http.StatusBadRequest, "same_warehouse"},
+	{entity.ErrInvalidSKU, http.StatusBadRequest, "invalid_sku"},
+	{entity.ErrInvalidVariantOptions, http.StatusBadRequest, "invalid_variant_options"},
+	{entity.ErrInvalidImage, http.StatusBadRequest, "invalid_image"},
+	{pkgEntity.ErrInvalidCursor, http.StatusBadRequest, "invalid_cursor"},
+	{pkgEntity.ErrCursorMismatch, http.StatusBadRequest, "invalid_cursor"},
+	{entity.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{entity.ErrRefreshTokenInvalid, http.StatusUnauthorized, "invalid_refresh_token"},
+	{entity.ErrRefreshTokenExpired, http.StatusUnauthorized, "refresh_token_expired"},
+	{entity.ErrRefreshTokenReused, http.StatusUnauthorized, "refresh_token_reused"},
+	{gorm.ErrRecordNotFound, http.StatusNotFound, "not_found"},
+	{entity.ErrEmailAlreadyExists, http.StatusConflict, "email_already_exists"},
+	{entity.ErrCategoryHasChildren, http.StatusConflict, "category_has_children"},
+	{entity.ErrInsufficientStock, http.StatusConflict, "insufficient_stock"},
+	{entity.ErrReservationNotHeld, http.StatusConflict, "reservation_not_held"},
+	{entity.ErrReservationExpired, http.StatusConflict, "reservation_expired"},
+	{entity.ErrWarehouseCodeExists, http.StatusConflict, "warehouse_code_exists"},
+	{entity.ErrSKUExists, http.StatusConflict, "sku_exists"},
+	{entity.ErrVariantOptionsExist, http.StatusConflict, "variant_options_exist"},
+	{entity.ErrTooManyImages, http.StatusConflict, "too_many_images"},
+	{entity.ErrCartFull, http.StatusConflict, "cart_full"},
+	{entity.ErrCategoryNotFound, http.StatusUnprocessableEntity, "category_not_found"},
+	{entity.ErrCategoryCycle, http.StatusUnprocessableEntity, "category_cycle"},
+	{entity.ErrWarehouseNotFound, http.StatusUnprocessableEntity, "warehouse_not_found"},
+	{entity.ErrProductUnavailable, http.StatusUnprocessableEntity, "product_unavailable"},
+	{entity.ErrExchangeRateUnavailable, http.StatusUnprocessableEntity, "exchange_rate_unavailable"},
+	{entity.ErrVersionConflict, http.StatusPreconditionFailed, "version_conflict"},
+	{errIfMatchInvalid, http.StatusPreconditionFailed, "version_conflict"},
+	{errIfMatchWeak, http.StatusPreconditionFailed, "weak_etag"},
+	{entity.ErrImageTooLarge, http.StatusRequestEntityTooLarge, "image_too_large"},
+	{entity.ErrUnsupportedImageType, http.StatusUnsupportedMediaType, "unsupported_image_type"},
+	{errUnsupportedPatch, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+	{errIfMatchRequired, http.StatusPreconditionRequired, "precondition_required"},
+}
+
+var problemDetails = map[error]string{
+	gorm.ErrRecordNotFound: "the requested resource does not exist",
+}
+
+// WriteError renders err as a problem, choosing status and code from
+// problemMappings.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var ve dto.ValidationErrors
+	if errors.As(err, &ve) {
+		problem := newProblem(r, http.StatusUnprocessableEntity, "validation_failed", "the request has invalid fields")
+		problem.Errors = ve
+		writeProblem(w, problem)
+		return
+	}
+	var pe patchError
+	if errors.As(err, &pe) {
+		WriteProblem(w, r, http.StatusUnprocessableEntity, "invalid_patch_result", pe.Error())
+		return
+	}
+	for _, m := range problemMappings {
+		if errors.Is(err, m.err) {
+			detail, ok := problemDetails[m.err]
+			if !ok {
+				detail = m.err.Error()
+			}
+			WriteProblem(w, r, m.status, m.code, detail)
+			return
+		}
+	}
+	log.Printf("request %s: %v", middleware.GetReqID(r.Context()), err)
+	WriteProblem(w, r, http.StatusInternalServerError, "internal_error", "an unexpected error occurred")
+}
+
+func WriteProblem(w http.ResponseWriter, r *http.Request, status int, code, detail string) {
+	writeProblem(w, newProblem(r, status, code, detail))
+}
+
+func newProblem(r *http.Request, status int, code, detail string) Problem {
+	return Problem{
+		Type:      "/problems/" + code,
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Code:      code,
+		Instance:  r.URL.Path,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+}
+
+func writeProblem(w http.ResponseWriter, problem Problem) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
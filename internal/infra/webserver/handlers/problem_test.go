@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestWriteProblem(t *testing.T) {
+	var req *http.Request
+	middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/products/1", nil))
+
+	rec := httptest.NewRecorder()
+	WriteProblem(rec, req, http.StatusForbidden, "forbidden", "only the owner can update this product")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	var problem Problem
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&problem))
+	assert.Equal(t, "/problems/forbidden", problem.Type)
+	assert.Equal(t, "Forbidden", problem.Title)
+	assert.Equal(t, http.StatusForbidden, problem.Status)
+	assert.Equal(t, "forbidden", problem.Code)
+	assert.Equal(t, "/products/1", problem.Instance)
+	assert.NotEmpty(t, problem.RequestID)
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{entity.ErrNameIsRequired, http.StatusBadRequest, "name_required"},
+		{entity.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+		{gorm.ErrRecordNotFound, http.StatusNotFound, "not_found"},
+		{entity.ErrEmailAlreadyExists, http.StatusConflict, "email_already_exists"},
+		{fmt.Errorf("update: %w", entity.ErrVersionConflict), http.StatusPreconditionFailed, "version_conflict"},
+		{errIfMatchInvalid, http.StatusPreconditionFailed, "version_conflict"},
+		{errIfMatchRequired, http.StatusPreconditionRequired, "precondition_required"},
+		{errUnsupportedPatch, http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{patchError{entity.ErrPriceIsRequired}, http.StatusUnprocessableEntity, "invalid_patch_result"},
+		{dto.ValidationErrors{{Field: "name", Code: "required", Message: "name is required"}}, http.StatusUnprocessableEntity, "validation_failed"},
+		{errors.New("dial tcp: connection refused"), http.StatusInternalServerError, "internal_error"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		WriteError(rec, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+		assert.Equal(t, tt.status, rec.Code, tt.err.Error())
+
+		var problem Problem
+		assert.Nil(t, json.NewDecoder(rec.Body).Decode(&problem))
+		assert.Equal(t, tt.code, problem.Code, tt.err.Error())
+		assert.NotContains(t, problem.Detail, "connection refused")
+	}
+}
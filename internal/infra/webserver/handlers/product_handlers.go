@@ -2,23 +2,39 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/storage"
 	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
 	"github.com/go-chi/chi"
+	"gorm.io/gorm"
 )
 
 type ProductHandler struct {
-	ProductDB database.ProductInterface
+	ProductDB      database.ProductInterface
+	ExchangeRateDB database.ExchangeRateInterface
+	Store          database.StoreInterface
+	Storage        storage.Storage
+	CursorSecret   []byte
 }
 
-func NewProductHandler(db database.ProductInterface) *ProductHandler {
+func NewProductHandler(db database.ProductInterface, exchangeRateDB database.ExchangeRateInterface, store database.StoreInterface, storage storage.Storage, cursorSecret []byte) *ProductHandler {
 	return &ProductHandler{
-		ProductDB: db,
+		ProductDB:      db,
+		ExchangeRateDB: exchangeRateDB,
+		Store:          store,
+		Storage:        storage,
+		CursorSecret:   cursorSecret,
 	}
 }
 
@@ -30,32 +46,46 @@ func NewProductHandler(db database.ProductInterface) *ProductHandler {
 // @Produce  		json
 // @Param 			request		body			dto.CreateProductInput true "product request"
 // @Success 		201
-// @Failure 		400 			{object}	Error
-// @Failure 		500 			{object}	Error
+// @Failure 		400 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
 // @Router 			/products [post]
 // @Security 		ApiKeyAuth
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	var input dto.CreateProductInput
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
 		return
 	}
-	product, err := entity.NewProduct(input.Name, input.Price)
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	price, _ := input.Price.Money()
+	product, err := entity.NewProduct(input.Name, price)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	product.Tags, _ = entity.NormalizeTags(input.Tags)
+	product.Images = []entity.ProductImage{}
+	userID, _ := currentUser(r)
+	ownerID, err := pkgEntity.ParseID(userID)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteProblem(w, r, http.StatusUnauthorized, "unauthorized", "invalid token subject")
 		return
 	}
+	product.OwnerID = &ownerID
 
-	err = h.ProductDB.Create(product)
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.ProductDB.Create(product); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionCreate, auditEntityProduct, product.ID.String(), nil, product)
+	})
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -67,38 +97,214 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 // @Tags 				products
 // @Accept  		json
 // @Produce  		json
-// @Param 			page		query			string	false	"page number"
-// @Param 			limit		query			string	false	"limit"
-// @Param 			sort		query			string	false	"sort"
-// @Success 		200 		{object}	[]entity.Product
-// @Failure 		500 			{object}	Error
+// @Param 			page						query			string	false	"page number"
+// @Param 			cursor					query			string	false	"opaque cursor from next_cursor; send it empty to start keyset pagination from the beginning"
+// @Param 			limit						query			string	false	"limit"
+// @Param 			sort						query			string	false	"sort direction" Enums(asc, desc)
+// @Param 			sort_by					query			string	false	"sort field" Enums(name, price, created_at)
+// @Param 			q								query			string	false	"case-insensitive search on the product name"
+// @Param 			price_currency	query			string	false	"only products priced in this ISO 4217 code; required with min_price, max_price and sort_by=price"
+// @Param 			min_price				query			string	false	"minimum price as a decimal amount in price_currency, e.g. 12.34"
+// @Param 			max_price				query			string	false	"maximum price as a decimal amount in price_currency, e.g. 12.34"
+// @Param 			created_after		query			string	false	"created at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param 			created_before	query			string	false	"created before (RFC 3339 or YYYY-MM-DD)"
+// @Param 			owner						query			string	false	"only products created by the caller" Enums(me)
+// @Param 			currency				query			string	false	"ISO 4217 code to also show prices in"
+// @Param 			category				query			string	false	"only products in this category" Format(uuid)
+// @Param 			include_descendants	query		bool		false	"with category, also products in its subcategories"
+// @Param 			tags						query			string	false	"comma-separated tags"
+// @Param 			tag_mode				query			string	false	"match products with any (default) or all of the tags" Enums(any, all)
+// @Success 		200 						{object}	dto.ProductListOutput
+// @Header 			200 						{string}	Link						"first, prev, next and last page links"
+// @Header 			200 						{integer}	X-Total-Count		"number of products matching the filters"
+// @Failure 		400 						{object}	Problem
+// @Failure 		500 						{object}	Problem
 // @Router 			/products [get]
 // @Security 		ApiKeyAuth
 func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
-	page := r.URL.Query().Get("page")
-	limit := r.URL.Query().Get("limit")
-	sort := r.URL.Query().Get("sort")
+	query, err := productQueryFromRequest(r)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	currency, err := currencyParam(r)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+
+	if r.URL.Query().Has("cursor") {
+		h.getProductsAfterCursor(w, r, query, currency)
+		return
+	}
 
-	pageInt, err := strconv.Atoi(page)
+	products, total, err := h.ProductDB.FindAll(query)
 	if err != nil {
-		pageInt = 0
+		WriteError(w, r, err)
+		return
 	}
-	limitInt, err := strconv.Atoi(limit)
+	items, err := h.productOutputs(currency, products...)
 	if err != nil {
-		limitInt = 10
+		WriteError(w, r, err)
+		return
+	}
+
+	query = query.Normalize()
+	links := newPageLinks(r.URL, query.Page, query.Limit, total)
+	output := dto.ProductListOutput{
+		Items:      items,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      total,
+		TotalPages: links.TotalPages,
+		Next:       links.Next,
+		Prev:       links.Prev,
+	}
+	if links.Next != "" && (query.SortBy == "" || query.SortBy == "created_at") {
+		output.NextCursor = pkgEntity.EncodeCursor(query.CursorAfter(products[len(products)-1]), h.CursorSecret)
+	}
+
+	links.writeHeaders(w, total)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+func (h *ProductHandler) getProductsAfterCursor(w http.ResponseWriter, r *http.Request, query database.ProductQuery, currency string) {
+	if query.SortBy != "" && query.SortBy != "created_at" {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", "cursor pagination only supports sort_by=created_at")
+		return
+	}
+	var after *pkgEntity.Cursor
+	if token := r.URL.Query().Get("cursor"); token != "" {
+		cursor, err := pkgEntity.DecodeCursor(token, h.CursorSecret)
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+		after = &cursor
 	}
 
-	products, err := h.ProductDB.FindAll(pageInt, limitInt, sort)
+	products, next, err := h.ProductDB.FindAfter(query, after)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	total, err := h.ProductDB.Count(query)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	items, err := h.productOutputs(currency, products...)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
 		return
 	}
 
+	query = query.Normalize()
+	output := dto.ProductListOutput{
+		Items:      items,
+		Limit:      query.Limit,
+		Total:      total,
+		TotalPages: int((total + int64(query.Limit) - 1) / int64(query.Limit)),
+	}
+	if next != nil {
+		output.NextCursor = pkgEntity.EncodeCursor(*next, h.CursorSecret)
+		values := r.URL.Query()
+		values.Del("page")
+		values.Set("cursor", output.NextCursor)
+		output.Next = (&url.URL{Path: r.URL.Path, RawQuery: values.Encode()}).String()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, output.Next))
+	}
+
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(products)
+	json.NewEncoder(w).Encode(output)
+}
+
+func productQueryFromRequest(r *http.Request) (database.ProductQuery, error) {
+	values := r.URL.Query()
+	query := database.ProductQuery{
+		Search:  values.Get("q"),
+		SortBy:  values.Get("sort_by"),
+		SortDir: values.Get("sort"),
+	}
+
+	page, err := strconv.Atoi(values.Get("page"))
+	if err != nil {
+		page = 0
+	}
+	limit, err := strconv.Atoi(values.Get("limit"))
+	if err != nil {
+		limit = 10
+	}
+	query.Page, query.Limit = page, limit
+
+	if query.SortBy != "" && !database.IsValidProductSort(query.SortBy) {
+		return query, fmt.Errorf("invalid sort_by %q", query.SortBy)
+	}
+	if query.SortDir != "" && query.SortDir != "asc" && query.SortDir != "desc" {
+		return query, fmt.Errorf("invalid sort %q", query.SortDir)
+	}
+	query.PriceCurrency = strings.ToUpper(values.Get("price_currency"))
+	if query.PriceCurrency != "" && !pkgEntity.IsValidCurrency(query.PriceCurrency) {
+		return query, fmt.Errorf("invalid price_currency %q", values.Get("price_currency"))
+	}
+	if query.PriceCurrency == "" && (values.Get("min_price") != "" || values.Get("max_price") != "" || query.SortBy == "price") {
+		return query, errors.New("price_currency is required with min_price, max_price and sort_by=price")
+	}
+	if query.MinPrice, err = priceParam(values.Get("min_price"), "min_price", query.PriceCurrency); err != nil {
+		return query, err
+	}
+	if query.MaxPrice, err = priceParam(values.Get("max_price"), "max_price", query.PriceCurrency); err != nil {
+		return query, err
+	}
+	if query.CreatedAfter, err = timeParam(values.Get("created_after"), "created_after"); err != nil {
+		return query, err
+	}
+	if query.CreatedBefore, err = timeParam(values.Get("created_before"), "created_before"); err != nil {
+		return query, err
+	}
+	if values.Get("owner") == "me" {
+		query.OwnerID, _ = currentUser(r)
+	}
+	query.Category = values.Get("category")
+	query.IncludeDescendants = values.Get("include_descendants") == "true"
+	if tags := values.Get("tags"); tags != "" {
+		if query.Tags, err = entity.NormalizeTags(strings.Split(tags, ",")); err != nil {
+			return query, fmt.Errorf("invalid tags %q", tags)
+		}
+	}
+	query.TagMode = values.Get("tag_mode")
+	if query.TagMode != "" && query.TagMode != "any" && query.TagMode != "all" {
+		return query, fmt.Errorf("invalid tag_mode %q", query.TagMode)
+	}
+	return query, nil
+}
+
+// priceParam reads a decimal amount in the minor units of currency.
+func priceParam(value, name, currency string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	money, err := pkgEntity.ParseMoney(value, currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return &money.Amount, nil
+}
+
+func timeParam(value, name string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s %q", name, value)
 }
 
 // Get product godoc
@@ -107,30 +313,85 @@ func (h *ProductHandler) GetProducts(w http.ResponseWriter, r *http.Request) {
 // @Tags 				products
 // @Accept  		json
 // @Produce  		json
-// @Param 			id		path			string	true	"product ID" Format(uuid)
-// @Success 		200 	{object}	entity.Product
-// @Failure 		400 	{object}	Error
-// @Failure 		404 	{object}	Error
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			currency	query			string	false	"ISO 4217 code to also show the price in"
+// @Success 		200 			{object}	dto.ProductOutput
+// @Header 			200 			{string}	ETag	"product version, to be sent back in If-Match"
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		422 			{object}	Problem
 // @Router 			/products/{id} [get]
 // @Security 		ApiKeyAuth
 func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: "ID is required"}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, entity.ErrIDIsRequired)
+		return
+	}
+	currency, err := currencyParam(r)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", err.Error())
 		return
 	}
 	product, err := h.ProductDB.FindByID(id)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
+		return
+	}
+	output, err := h.productOutput(currency, *product)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(product.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Get price history godoc
+// @Summary 		Get product price history
+// @Description List the price changes of a product, oldest first
+// @Tags 				products
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Param 			from	query			string	false	"changed at or after (RFC 3339 or YYYY-MM-DD)"
+// @Param 			to		query			string	false	"changed before (RFC 3339 or YYYY-MM-DD)"
+// @Success 		200 	{array}		entity.PriceChange
+// @Failure 		400 	{object}	Problem
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/prices [get]
+// @Security 		ApiKeyAuth
+func (h *ProductHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	from, err := timeParam(r.URL.Query().Get("from"), "from")
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	to, err := timeParam(r.URL.Query().Get("to"), "to")
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	_, err = h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	changes, err := h.ProductDB.FindPriceHistory(id, from, to)
+	if err != nil {
+		WriteError(w, r, err)
 		return
 	}
+	if changes == nil {
+		changes = []entity.PriceChange{}
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(product)
+	json.NewEncoder(w).Encode(changes)
 }
 
 // Update product godoc
@@ -139,87 +400,340 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 // @Tags 				products
 // @Accept  		json
 // @Produce  		json
-// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			If-Match	header		string	true	"ETag returned by GET /products/{id}"
 // @Param 			request		body			dto.UpdateProductInput true "product request"
 // @Success 		200
-// @Failure 		400 	{object}	Error
-// @Failure 		404 	{object}	Error
-// @Failure 		500 	{object}	Error
+// @Header 			200 			{string}	ETag	"new product version"
+// @Failure 		400 			{object}	Problem
+// @Failure 		403 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		412 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		428 			{object}	Problem
+// @Failure 		500 			{object}	Problem
 // @Router 			/products/{id} [put]
 // @Security 		ApiKeyAuth
 func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: "ID is required"}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, entity.ErrIDIsRequired)
+		return
+	}
+	match, err := parseIfMatch(r)
+	if err != nil {
+		WriteError(w, r, err)
 		return
 	}
-	var product entity.Product
-	err := json.NewDecoder(r.Body).Decode(&product)
+	var input dto.UpdateProductInput
+	err = json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
 		return
 	}
+	price, _ := input.Price.Money()
+	product := entity.Product{Name: input.Name, Price: price}
 	product.ID, err = pkgEntity.ParseID(id)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, entity.ErrInvalidID)
 		return
 	}
 	productFound, err := h.ProductDB.FindByID(id)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
+		return
+	}
+	if !authorizeOwner(w, r, productFound, "update") {
 		return
 	}
+	product.OwnerID = productFound.OwnerID
+	product.Tags = productFound.Tags
+	product.Images = productFound.Images
+	product.Stock = productFound.Stock
 	product.CreatedAt = productFound.CreatedAt
-	err = h.ProductDB.Update(&product)
+	product.Version, err = match.version(productFound.Version)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	userID, _ := currentUser(r)
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.ProductDB.Update(&product, userID); err != nil {
+			return err
+		}
+		if err := tx.CartDB.RevalidateProduct(id); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityProduct, id, productFound, &product)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(product.Version))
+}
+
+// Patch product godoc
+// @Summary 		Patch product
+// @Description Partially update a product with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) applied to {"name", "price"}. Only the changed columns are written.
+// @Tags 				products
+// @Accept  		application/merge-patch+json,application/json-patch+json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			If-Match	header		string	true	"ETag returned by GET /products/{id}"
+// @Param 			request		body			dto.PatchProductDocument true "merge patch, or an array of JSON Patch operations"
+// @Success 		200 			{object}	dto.ProductOutput
+// @Header 			200 			{string}	ETag	"new product version"
+// @Failure 		400 			{object}	Problem
+// @Failure 		403 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		412 			{object}	Problem
+// @Failure 		415 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		428 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id} [patch]
+// @Security 		ApiKeyAuth
+func (h *ProductHandler) PatchProduct(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	match, err := parseIfMatch(r)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	patch, err := io.ReadAll(r.Body)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
 		return
 	}
+	product, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if !authorizeOwner(w, r, product, "update") {
+		return
+	}
+	if _, err := match.version(product.Version); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	before := *product
+	changed, err := applyProductPatch(product, r.Header.Get("Content-Type"), patch)
+	if _, ok := err.(patchError); !ok && err != nil && err != errUnsupportedPatch {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if len(changed) > 0 {
+		userID, _ := currentUser(r)
+		err = h.Store.Transaction(func(tx database.Repositories) error {
+			if err := tx.ProductDB.UpdateColumns(product, userID, changed...); err != nil {
+				return err
+			}
+			if err := tx.CartDB.RevalidateProduct(id); err != nil {
+				return err
+			}
+			return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityProduct, id, &before, product)
+		})
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+	}
+
+	output, err := h.productOutput("", *product)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(product.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
 }
 
 // Delete product godoc
 // @Summary 		Delete product
-// @Description Delete product
+// @Description Move a product to the trash. With purge=true the product is removed permanently (admin only), whether it is in the trash or not.
 // @Tags 				products
 // @Accept  		json
 // @Produce  		json
-// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			If-Match	header		string	false	"ETag returned by GET /products/{id}; required unless purging"
+// @Param 			purge			query			bool		false	"delete permanently"
 // @Success 		200
-// @Failure 		400 	{object}	Error
-// @Failure 		404 	{object}	Error
-// @Failure 		500 	{object}	Error
+// @Failure 		400 			{object}	Problem
+// @Failure 		403 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		412 			{object}	Problem
+// @Failure 		428 			{object}	Problem
+// @Failure 		500 			{object}	Problem
 // @Router 			/products/{id} [delete]
 // @Security 		ApiKeyAuth
 func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: "ID is required"}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, entity.ErrIDIsRequired)
+		return
+	}
+	if r.URL.Query().Get("purge") == "true" {
+		if _, role := currentUser(r); role != entity.RoleAdmin {
+			WriteProblem(w, r, http.StatusForbidden, "forbidden", "only admins can purge products")
+			return
+		}
+		var purged *entity.Product
+		err := h.Store.Transaction(func(tx database.Repositories) error {
+			var err error
+			purged, err = tx.ProductDB.FindAnyByID(id)
+			if err != nil {
+				return err
+			}
+			if err := tx.ProductDB.Purge(id); err != nil {
+				return err
+			}
+			return recordAudit(tx.AuditDB, r, entity.AuditActionPurge, auditEntityProduct, id, purged, nil)
+		})
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+		deleteImageFiles(r, h.Storage, purged.Images...)
 		return
 	}
-	_, err := h.ProductDB.FindByID(id)
+	match, err := parseIfMatch(r)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
 		return
 	}
-	err = h.ProductDB.Delete(id)
+	productFound, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if !authorizeOwner(w, r, productFound, "delete") {
+		return
+	}
+	version, err := match.version(productFound.Version)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.ProductDB.Delete(id, version); err != nil {
+			return err
+		}
+		if err := tx.CartDB.RevalidateProduct(id); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionDelete, auditEntityProduct, id, productFound, nil)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// Get trash godoc
+// @Summary 		List deleted products
+// @Description List products in the trash. Admins see every deleted product, other users only their own.
+// @Tags 				products
+// @Accept  		json
+// @Produce  		json
+// @Param 			page		query			string	false	"page number"
+// @Param 			limit		query			string	false	"limit"
+// @Param 			sort		query			string	false	"sort direction" Enums(asc, desc)
+// @Param 			sort_by	query			string	false	"sort field" Enums(name, price, created_at)
+// @Param 			price_currency	query	string	false	"only products priced in this ISO 4217 code; required with sort_by=price"
+// @Param 			q				query			string	false	"case-insensitive search on the product name"
+// @Success 		200 		{object}	dto.ProductListOutput
+// @Failure 		400 		{object}	Problem
+// @Failure 		500 		{object}	Problem
+// @Router 			/products/trash [get]
+// @Security 		ApiKeyAuth
+func (h *ProductHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	query, err := productQueryFromRequest(r)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "invalid_parameter", err.Error())
+		return
+	}
+	userID, role := currentUser(r)
+	if role != entity.RoleAdmin {
+		query.OwnerID = userID
+	}
+
+	products, total, err := h.ProductDB.FindDeleted(query)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
 		return
 	}
+	items, err := h.productOutputs("", products...)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	query = query.Normalize()
+	links := newPageLinks(r.URL, query.Page, query.Limit, total)
+	output := dto.ProductListOutput{
+		Items:      items,
+		Page:       query.Page,
+		Limit:      query.Limit,
+		Total:      total,
+		TotalPages: links.TotalPages,
+		Next:       links.Next,
+		Prev:       links.Prev,
+	}
+
+	links.writeHeaders(w, total)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Restore product godoc
+// @Summary 		Restore product
+// @Description Take a product out of the trash
+// @Tags 				products
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Success 		200
+// @Failure 		403 	{object}	Problem
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/restore [post]
+// @Security 		ApiKeyAuth
+func (h *ProductHandler) RestoreProduct(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	productFound, err := h.ProductDB.FindDeletedByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if !authorizeOwner(w, r, productFound, "restore") {
+		return
+	}
+	restored := *productFound
+	restored.DeletedAt = gorm.DeletedAt{}
+	restored.Version++
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.ProductDB.Restore(id); err != nil {
+			return err
+		}
+		if err := tx.CartDB.RevalidateProduct(id); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionRestore, auditEntityProduct, id, productFound, &restored)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+	}
 }
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductQueryFromRequest_PriceCurrency(t *testing.T) {
+	query, err := productQueryFromRequest(httptest.NewRequest("GET", "/products?price_currency=jpy&min_price=100&sort_by=price", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, "JPY", query.PriceCurrency)
+	assert.Equal(t, int64(100), *query.MinPrice)
+
+	for _, target := range []string{
+		"/products?min_price=1.00",
+		"/products?max_price=1.00",
+		"/products?sort_by=price",
+		"/products?price_currency=XXX&min_price=1.00",
+		"/products?price_currency=JPY&min_price=1.50",
+	} {
+		_, err := productQueryFromRequest(httptest.NewRequest("GET", target, nil))
+		assert.NotNil(t, err, target)
+	}
+}
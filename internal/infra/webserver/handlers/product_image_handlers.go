@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/storage"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/thumbnail"
+	"github.com/go-chi/chi"
+)
+
+const (
+	thumbnailSize = 256
+	// multipartOverhead allows for the boundaries and headers around the
+	// uploaded file.
+	multipartOverhead = 64 << 10
+)
+
+var errImageFieldMissing = errors.New("the image form field is required")
+
+type ProductImageHandler struct {
+	ImageDB   database.ProductImageInterface
+	ProductDB database.ProductInterface
+	Store     database.StoreInterface
+	Storage   storage.Storage
+	MaxBytes  int64
+}
+
+func NewProductImageHandler(db database.ProductImageInterface, productDB database.ProductInterface, store database.StoreInterface, storage storage.Storage, maxBytes int64) *ProductImageHandler {
+	return &ProductImageHandler{
+		ImageDB:   db,
+		ProductDB: productDB,
+		Store:     store,
+		Storage:   storage,
+		MaxBytes:  maxBytes,
+	}
+}
+
+// Get product images godoc
+// @Summary 		List product images
+// @Description List the images of a product in upload order
+// @Tags 				images
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Success 		200 	{array}		entity.ProductImage
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/images [get]
+// @Security 		ApiKeyAuth
+func (h *ProductImageHandler) GetImages(w http.ResponseWriter, r *http.Request) {
+	product, err := h.ProductDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(product.Images)
+}
+
+// Upload product image godoc
+// @Summary 		Upload product image
+// @Description Upload a JPEG, PNG or GIF image in the "image" field of a multipart form. The type is detected from the content, not the file name, and a thumbnail is generated. Editors can only change their own products.
+// @Tags 				images
+// @Accept  		mpfd
+// @Produce  		json
+// @Param 			id			path			string	true	"product ID" Format(uuid)
+// @Param 			image		formData	file		true	"image file"
+// @Success 		201 		{object}	entity.ProductImage
+// @Failure 		400 		{object}	Problem
+// @Failure 		403 		{object}	Problem
+// @Failure 		404 		{object}	Problem
+// @Failure 		409 		{object}	Problem
+// @Failure 		413 		{object}	Problem
+// @Failure 		415 		{object}	Problem
+// @Failure 		500 		{object}	Problem
+// @Router 			/products/{id}/images [post]
+// @Security 		ApiKeyAuth
+func (h *ProductImageHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
+	before, ok := ownedProduct(w, r, h.ProductDB)
+	if !ok {
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, h.MaxBytes+multipartOverhead)
+	data, err := readImageField(r, h.MaxBytes)
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case err == entity.ErrImageTooLarge || errors.As(err, &maxBytesErr):
+		WriteError(w, r, entity.ErrImageTooLarge)
+		return
+	case err != nil:
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	upload, err := processImage(data)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	productImage, err := entity.NewProductImage(before.ID, upload.contentType, upload.thumbnailType,
+		int64(len(data)), upload.width, upload.height)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	productImage.URL = h.Storage.URL(productImage.Key)
+	productImage.ThumbnailURL = h.Storage.URL(productImage.ThumbnailKey)
+
+	err = h.Storage.Put(r.Context(), productImage.Key, upload.contentType, data)
+	if err == nil {
+		err = h.Storage.Put(r.Context(), productImage.ThumbnailKey, upload.thumbnailType, upload.thumbnail)
+	}
+	if err == nil {
+		err = h.Store.Transaction(func(tx database.Repositories) error {
+			if err := tx.ImageDB.Create(productImage); err != nil {
+				return err
+			}
+			return h.recordImageAudit(tx, r, before)
+		})
+	}
+	if err != nil {
+		deleteImageFiles(r, h.Storage, *productImage)
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(productImage)
+}
+
+// Delete product image godoc
+// @Summary 		Delete product image
+// @Description Delete a product image and its thumbnail
+// @Tags 				images
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			imageID		path			string	true	"image ID" Format(uuid)
+// @Success 		200
+// @Failure 		403 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id}/images/{imageID} [delete]
+// @Security 		ApiKeyAuth
+func (h *ProductImageHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
+	before, ok := ownedProduct(w, r, h.ProductDB)
+	if !ok {
+		return
+	}
+	productImage, err := h.ImageDB.FindByID(before.ID.String(), chi.URLParam(r, "imageID"))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.ImageDB.Delete(before.ID.String(), productImage.ID.String()); err != nil {
+			return err
+		}
+		return h.recordImageAudit(tx, r, before)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	deleteImageFiles(r, h.Storage, *productImage)
+}
+
+// recordImageAudit records the change of a product's images as an update
+// of the product.
+func (h *ProductImageHandler) recordImageAudit(tx database.Repositories, r *http.Request, before *entity.Product) error {
+	after, err := tx.ProductDB.FindByID(before.ID.String())
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityProduct, before.ID.String(), before, after)
+}
+
+// deleteImageFiles removes the stored files of images. Failures only leave
+// unreferenced files behind, so they are logged instead of returned.
+func deleteImageFiles(r *http.Request, files storage.Storage, productImages ...entity.ProductImage) {
+	for _, productImage := range productImages {
+		for _, key := range []string{productImage.Key, productImage.ThumbnailKey} {
+			if err := files.Delete(r.Context(), key); err != nil {
+				log.Printf("deleting image file %s: %v", key, err)
+			}
+		}
+	}
+}
+
+// readImageField reads the "image" part of a multipart request, failing
+// with entity.ErrImageTooLarge when it exceeds maxBytes.
+func readImageField(r *http.Request, maxBytes int64) ([]byte, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errImageFieldMissing
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() != "image" {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(part, maxBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > maxBytes {
+			return nil, entity.ErrImageTooLarge
+		}
+		return data, nil
+	}
+}
+
+type processedImage struct {
+	contentType   string
+	width, height int
+	thumbnail     []byte
+	thumbnailType string
+}
+
+// processImage checks that data is an image of a supported type, judging
+// by its content alone, and renders its thumbnail.
+func processImage(data []byte) (*processedImage, error) {
+	contentType := http.DetectContentType(data)
+	if !entity.IsSupportedImageType(contentType) {
+		return nil, entity.ErrUnsupportedImageType
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, entity.ErrInvalidImage
+	}
+	if config.Width*config.Height > entity.MaxImagePixels {
+		return nil, entity.ErrImageTooLarge
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, entity.ErrInvalidImage
+	}
+	var buf bytes.Buffer
+	thumbnailType, err := thumbnail.Encode(&buf, thumbnail.New(img, thumbnailSize), format)
+	if err != nil {
+		return nil, err
+	}
+	return &processedImage{
+		contentType:   contentType,
+		width:         config.Width,
+		height:        config.Height,
+		thumbnail:     buf.Bytes(),
+		thumbnailType: thumbnailType,
+	}, nil
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database/migrations"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/storage"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/jwtauth"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func encodedImage(t *testing.T, width, height int, encode func(*bytes.Buffer, image.Image) error) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+	return encodedImage(t, width, height, func(buf *bytes.Buffer, img image.Image) error {
+		return png.Encode(buf, img)
+	})
+}
+
+func TestProcessImage(t *testing.T) {
+	upload, err := processImage(pngImage(t, 600, 300))
+	assert.Nil(t, err)
+	assert.Equal(t, "image/png", upload.contentType)
+	assert.Equal(t, 600, upload.width)
+	assert.Equal(t, 300, upload.height)
+	assert.Equal(t, "image/png", upload.thumbnailType)
+	thumb, err := png.DecodeConfig(bytes.NewReader(upload.thumbnail))
+	assert.Nil(t, err)
+	assert.Equal(t, 256, thumb.Width)
+	assert.Equal(t, 128, thumb.Height)
+
+	animated := encodedImage(t, 10, 10, func(buf *bytes.Buffer, img image.Image) error {
+		return gif.Encode(buf, img, nil)
+	})
+	upload, err = processImage(animated)
+	assert.Nil(t, err)
+	assert.Equal(t, "image/gif", upload.contentType)
+	assert.Equal(t, "image/png", upload.thumbnailType)
+
+	_, err = processImage([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"))
+	assert.Equal(t, entity.ErrUnsupportedImageType, err)
+	_, err = processImage(pngImage(t, 10, 10)[:40])
+	assert.Equal(t, entity.ErrInvalidImage, err)
+}
+
+func uploadRequest(t *testing.T, productID, field string, data []byte) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile(field, "photo.txt")
+	part.Write(data)
+	form.Close()
+	r := httptest.NewRequest("POST", "/products/"+productID+"/images", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	return withUser(r, "admin", entity.RoleAdmin, map[string]string{"id": productID})
+}
+
+// withUser authenticates r as sub with role and sets the route parameters,
+// as the router and the JWT middlewares would.
+func withUser(r *http.Request, sub, role string, params map[string]string) *http.Request {
+	token, _, _ := jwtauth.New("HS256", []byte("secret"), nil).Encode(map[string]interface{}{"sub": sub, "role": role})
+	routeContext := chi.NewRouteContext()
+	for key, value := range params {
+		routeContext.URLParams.Add(key, value)
+	}
+	ctx := context.WithValue(jwtauth.NewContext(r.Context(), token, nil), chi.RouteCtxKey, routeContext)
+	return r.WithContext(ctx)
+}
+
+func TestUploadImage(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatal(err)
+	}
+	productDB := database.NewProduct(db)
+	dir := t.TempDir()
+	handler := NewProductImageHandler(database.NewProductImage(db), productDB, database.NewStore(db),
+		storage.NewLocal(dir, "http://localhost:8000/images"), 64<<10)
+	product, _ := entity.NewProduct("Shirt", brl(1000))
+	productDB.Create(product)
+	id := product.ID.String()
+
+	w := httptest.NewRecorder()
+	handler.UploadImage(w, uploadRequest(t, id, "image", pngImage(t, 300, 300)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var uploaded entity.ProductImage
+	json.NewDecoder(w.Body).Decode(&uploaded)
+	assert.Equal(t, "image/png", uploaded.ContentType)
+	assert.Equal(t, "http://localhost:8000/images/products/"+id+"/"+uploaded.ID.String()+".png", uploaded.URL)
+	_, err = os.Stat(filepath.Join(dir, "products", id, uploaded.ID.String()+"_thumb.png"))
+	assert.Nil(t, err)
+
+	found, _ := productDB.FindByID(id)
+	assert.Len(t, found.Images, 1)
+	assert.Equal(t, uploaded.ThumbnailURL, found.Images[0].ThumbnailURL)
+	_, audited, _ := database.NewAudit(db).FindAll(database.AuditQuery{EntityID: id})
+	assert.Equal(t, int64(1), audited)
+
+	cases := []struct {
+		field  string
+		data   []byte
+		status int
+	}{
+		{"photo", pngImage(t, 10, 10), http.StatusBadRequest},
+		{"image", []byte("plain text"), http.StatusUnsupportedMediaType},
+		{"image", bytes.Repeat([]byte{0}, 65<<10), http.StatusRequestEntityTooLarge},
+		{"image", bytes.Repeat([]byte{0}, 200<<10), http.StatusRequestEntityTooLarge},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		handler.UploadImage(w, uploadRequest(t, id, c.field, c.data))
+		assert.Equal(t, c.status, w.Code, w.Body.String())
+	}
+}
+
+func TestPurgeProductDeletesImageFiles(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := migrations.NewMigrator(db).Up(); err != nil {
+		t.Fatal(err)
+	}
+	productDB := database.NewProduct(db)
+	dir := t.TempDir()
+	files := storage.NewLocal(dir, "http://localhost:8000/images")
+	imageHandler := NewProductImageHandler(database.NewProductImage(db), productDB, database.NewStore(db), files, 64<<10)
+	productHandler := NewProductHandler(productDB, nil, database.NewStore(db), files, nil)
+	product, _ := entity.NewProduct("Shirt", brl(1000))
+	productDB.Create(product)
+	id := product.ID.String()
+
+	w := httptest.NewRecorder()
+	imageHandler.UploadImage(w, uploadRequest(t, id, "image", pngImage(t, 10, 10)))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var uploaded entity.ProductImage
+	json.NewDecoder(w.Body).Decode(&uploaded)
+
+	r := withUser(httptest.NewRequest("DELETE", "/products/"+id+"?purge=true", nil), "admin", entity.RoleAdmin, map[string]string{"id": id})
+	w = httptest.NewRecorder()
+	productHandler.DeleteProduct(w, r)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	for _, name := range []string{uploaded.ID.String() + ".png", uploaded.ID.String() + "_thumb.png"} {
+		_, err := os.Stat(filepath.Join(dir, "products", id, name))
+		assert.True(t, os.IsNotExist(err), name)
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+)
+
+// currencyParam reads the optional display currency of a product listing.
+func currencyParam(r *http.Request) (string, error) {
+	currency := strings.ToUpper(r.URL.Query().Get("currency"))
+	if currency != "" && !pkgEntity.IsValidCurrency(currency) {
+		return "", fmt.Errorf("invalid currency %q", r.URL.Query().Get("currency"))
+	}
+	return currency, nil
+}
+
+// productOutput prepares a product for the response, adding its price in
+// currency and the rate that was used when currency is set. It fails with
+// entity.ErrExchangeRateUnavailable when there is no rate to currency.
+func (h *ProductHandler) productOutput(currency string, product entity.Product) (dto.ProductOutput, error) {
+	output := dto.ProductOutput{Product: product}
+	if currency == "" {
+		return output, nil
+	}
+	if product.Price.Currency == currency {
+		output.DisplayPrice = dto.NewMoneyOutput(product.Price)
+		return output, nil
+	}
+	rate, err := h.ExchangeRateDB.Find(product.Price.Currency, currency)
+	if err != nil {
+		return output, err
+	}
+	price, err := rate.Convert(product.Price)
+	if err != nil {
+		return output, err
+	}
+	output.DisplayPrice = dto.NewMoneyOutput(price)
+	output.ExchangeRate = rate
+	return output, nil
+}
+
+// productOutputs prepares the products of a listing. A product whose
+// currency has no rate to currency is left without display price rather than
+// failing the whole response.
+func (h *ProductHandler) productOutputs(currency string, products ...entity.Product) ([]dto.ProductOutput, error) {
+	outputs := make([]dto.ProductOutput, len(products))
+	for i, product := range products {
+		output, err := h.productOutput(currency, product)
+		if err != nil && err != entity.ErrExchangeRateUnavailable {
+			return nil, err
+		}
+		outputs[i] = output
+	}
+	return outputs, nil
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type exchangeRateStoreStub map[string]*entity.ExchangeRate
+
+func (s exchangeRateStoreStub) Save(rate *entity.ExchangeRate) error {
+	s[rate.Base+"/"+rate.Quote] = rate
+	return nil
+}
+
+func (s exchangeRateStoreStub) Find(base, quote string) (*entity.ExchangeRate, error) {
+	rate, ok := s[base+"/"+quote]
+	if !ok {
+		return nil, entity.ErrExchangeRateUnavailable
+	}
+	return rate, nil
+}
+
+func (s exchangeRateStoreStub) FindAll() ([]entity.ExchangeRate, error) {
+	return nil, nil
+}
+
+func TestProductOutputs(t *testing.T) {
+	rates := exchangeRateStoreStub{}
+	rate, _ := entity.NewExchangeRate("BRL", "USD", "0.2")
+	rates.Save(rate)
+	h := NewProductHandler(nil, rates, nil, nil, nil)
+	product, _ := entity.NewProduct("Product 1", brl(1050))
+
+	outputs, err := h.productOutputs("", *product)
+	assert.Nil(t, err)
+	assert.Nil(t, outputs[0].DisplayPrice)
+
+	outputs, err = h.productOutputs("USD", *product)
+	assert.Nil(t, err)
+	assert.Equal(t, brl(1050), outputs[0].Price)
+	assert.Equal(t, &dto.MoneyOutput{Amount: "2.10", Currency: "USD"}, outputs[0].DisplayPrice)
+	assert.Equal(t, rate, outputs[0].ExchangeRate)
+
+	body, _ := json.Marshal(outputs[0])
+	assert.Contains(t, string(body), `"price":{"amount":"10.50","currency":"BRL"}`)
+	assert.Contains(t, string(body), `"display_price":{"amount":"2.10","currency":"USD"}`)
+	assert.Contains(t, string(body), `"rate":"0.2"`)
+
+	outputs, err = h.productOutputs("BRL", *product)
+	assert.Nil(t, err)
+	assert.Equal(t, &dto.MoneyOutput{Amount: "10.50", Currency: "BRL"}, outputs[0].DisplayPrice)
+	assert.Nil(t, outputs[0].ExchangeRate)
+
+	euros, _ := entity.NewProduct("Product 2", pkgEntity.Money{Amount: 500, Currency: "EUR"})
+	outputs, err = h.productOutputs("USD", *product, *euros)
+	assert.Nil(t, err)
+	assert.Equal(t, &dto.MoneyOutput{Amount: "2.10", Currency: "USD"}, outputs[0].DisplayPrice)
+	assert.Nil(t, outputs[1].DisplayPrice)
+	assert.Nil(t, outputs[1].ExchangeRate)
+
+	_, err = h.productOutput("USD", *euros)
+	assert.Equal(t, entity.ErrExchangeRateUnavailable, err)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+var errUnsupportedPatch = errors.New("Content-Type must be " + mergePatchContentType + " or " + jsonPatchContentType)
+
+// patchError is a patch that could be applied but produced a document that
+// is not a valid product.
+type patchError struct {
+	err error
+}
+
+func (e patchError) Error() string {
+	return e.err.Error()
+}
+
+// applyProductPatch applies an RFC 7396 merge patch or an RFC 6902 JSON
+// patch to the editable fields of product, validates the result and returns
+// the columns that changed. Errors from a malformed patch are returned as is;
+// a well-formed patch producing an invalid product yields a patchError.
+func applyProductPatch(product *entity.Product, contentType string, patch []byte) ([]string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	doc, err := json.Marshal(dto.PatchProductDocument{
+		Name:  product.Name,
+		Price: dto.PriceInput{Amount: product.Price.Decimal(), Currency: product.Price.Currency},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch mediaType {
+	case mergePatchContentType:
+		patched, err = jsonpatch.MergePatch(doc, patch)
+	case jsonPatchContentType:
+		var ops jsonpatch.Patch
+		ops, err = jsonpatch.DecodePatch(patch)
+		if err == nil {
+			patched, err = ops.Apply(doc)
+			if err != nil {
+				return nil, patchError{err}
+			}
+		}
+	default:
+		return nil, errUnsupportedPatch
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result dto.PatchProductDocument
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return nil, patchError{fmt.Errorf("patched product is invalid: %w", err)}
+	}
+
+	if result.Price.Amount == "" {
+		return nil, patchError{entity.ErrPriceIsRequired}
+	}
+	price, err := result.Price.Money()
+	if err != nil {
+		return nil, patchError{err}
+	}
+
+	var changed []string
+	if result.Name != product.Name {
+		product.Name = result.Name
+		changed = append(changed, "name")
+	}
+	if price != product.Price {
+		product.Price = price
+		changed = append(changed, "price")
+	}
+	if err := product.Validate(); err != nil {
+		return nil, patchError{err}
+	}
+	return changed, nil
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func brl(amount int64) pkgEntity.Money {
+	return pkgEntity.Money{Amount: amount, Currency: "BRL"}
+}
+
+func TestApplyProductPatch_MergePatch(t *testing.T) {
+	product, _ := entity.NewProduct("Product 1", brl(1000))
+
+	changed, err := applyProductPatch(product, "application/merge-patch+json", []byte(`{"name": "Product 2"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"name"}, changed)
+	assert.Equal(t, "Product 2", product.Name)
+	assert.Equal(t, brl(1000), product.Price)
+
+	changed, err = applyProductPatch(product, "application/merge-patch+json; charset=utf-8", []byte(`{"price": 10}`))
+	assert.Nil(t, err)
+	assert.Empty(t, changed)
+
+	changed, err = applyProductPatch(product, "application/merge-patch+json", []byte(`{"price": {"amount": "12.50"}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"price"}, changed)
+	assert.Equal(t, brl(1250), product.Price)
+
+	changed, err = applyProductPatch(product, "application/merge-patch+json", []byte(`{"price": {"currency": "USD"}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"price"}, changed)
+	assert.Equal(t, pkgEntity.Money{Amount: 1250, Currency: "USD"}, product.Price)
+}
+
+func TestApplyProductPatch_JSONPatch(t *testing.T) {
+	product, _ := entity.NewProduct("Product 1", brl(1000))
+
+	changed, err := applyProductPatch(product, "application/json-patch+json", []byte(`[
+		{"op": "test", "path": "/price/amount", "value": "10.00"},
+		{"op": "replace", "path": "/price/amount", "value": "25.00"}
+	]`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"price"}, changed)
+	assert.Equal(t, brl(2500), product.Price)
+
+	_, err = applyProductPatch(product, "application/json-patch+json", []byte(`[{"op": "test", "path": "/price/amount", "value": "10.00"}]`))
+	assert.IsType(t, patchError{}, err)
+	assert.Equal(t, brl(2500), product.Price)
+}
+
+func TestApplyProductPatch_Invalid(t *testing.T) {
+	product, _ := entity.NewProduct("Product 1", brl(1000))
+
+	_, err := applyProductPatch(product, "application/json", []byte(`{"price": 20}`))
+	assert.Equal(t, errUnsupportedPatch, err)
+
+	_, err = applyProductPatch(product, "application/merge-patch+json", []byte(`{"price": `))
+	assert.NotNil(t, err)
+	assert.False(t, errors.As(err, &patchError{}))
+
+	_, err = applyProductPatch(product, "application/merge-patch+json", []byte(`{"price": null}`))
+	assert.Equal(t, patchError{entity.ErrPriceIsRequired}, err)
+
+	_, err = applyProductPatch(product, "application/merge-patch+json", []byte(`{"price": {"currency": "XYZ"}}`))
+	assert.Equal(t, patchError{pkgEntity.ErrInvalidCurrency}, err)
+
+	_, err = applyProductPatch(product, "application/merge-patch+json", []byte(`{"owner_id": "d3021498-0507-4e06-8f9d-658fc7152578"}`))
+	assert.IsType(t, patchError{}, err)
+
+	_, err = applyProductPatch(product, "application/json-patch+json", []byte(`[{"op": "add", "path": "/id", "value": "x"}]`))
+	assert.IsType(t, patchError{}, err)
+}
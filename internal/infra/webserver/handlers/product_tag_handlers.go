@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/go-chi/chi"
+)
+
+// Get tags godoc
+// @Summary 		List tags
+// @Description List every tag in use with the number of products carrying it, ignoring products in the trash
+// @Tags 				products
+// @Accept  		json
+// @Produce  		json
+// @Success 		200 	{array}		entity.TagUsage
+// @Failure 		500 	{object}	Problem
+// @Router 			/tags [get]
+// @Security 		ApiKeyAuth
+func (h *ProductHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	usages, err := h.ProductDB.FindTags()
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if usages == nil {
+		usages = []entity.TagUsage{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(usages)
+}
+
+// Add product tags godoc
+// @Summary 		Add product tags
+// @Description Add tags to a product. Tags are trimmed and lowercased; tags the product already has are ignored.
+// @Tags 				products
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			request		body			dto.ProductTagsInput true "tags"
+// @Success 		200 			{array}		string
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id}/tags [post]
+// @Security 		ApiKeyAuth
+func (h *ProductHandler) AddProductTags(w http.ResponseWriter, r *http.Request) {
+	h.changeProductTags(w, r, database.ProductInterface.AddTags)
+}
+
+// Remove product tags godoc
+// @Summary 		Remove product tags
+// @Description Remove tags from a product; tags it does not have are ignored
+// @Tags 				products
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			request		body			dto.ProductTagsInput true "tags"
+// @Success 		200 			{array}		string
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id}/tags [delete]
+// @Security 		ApiKeyAuth
+func (h *ProductHandler) RemoveProductTags(w http.ResponseWriter, r *http.Request) {
+	h.changeProductTags(w, r, database.ProductInterface.RemoveTags)
+}
+
+func (h *ProductHandler) changeProductTags(w http.ResponseWriter, r *http.Request, change func(db database.ProductInterface, id string, tags []string) error) {
+	id := chi.URLParam(r, "id")
+	var input dto.ProductTagsInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	tags, _ := entity.NormalizeTags(input.Tags)
+
+	before, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	var after *entity.Product
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := change(tx.ProductDB, id, tags); err != nil {
+			return err
+		}
+		after, err = tx.ProductDB.FindByID(id)
+		if err != nil || after.Version == before.Version {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityProduct, id, before, after)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("ETag", versionETag(after.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(after.Tags)
+}
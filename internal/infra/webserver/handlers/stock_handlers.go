@@ -0,0 +1,414 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/go-chi/chi"
+)
+
+type StockHandler struct {
+	StockDB   database.StockInterface
+	ProductDB database.ProductInterface
+}
+
+func NewStockHandler(db database.StockInterface, productDB database.ProductInterface) *StockHandler {
+	return &StockHandler{
+		StockDB:   db,
+		ProductDB: productDB,
+	}
+}
+
+// Get stock godoc
+// @Summary 		Get product stock
+// @Description Get the quantity available for sale and the quantity held by reservations
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Success 		200 	{object}	dto.StockOutput
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/stock [get]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	product, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	reserved, err := h.StockDB.Reserved(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.StockOutput{ProductID: id, Available: product.Stock, Reserved: reserved})
+}
+
+// Get stock adjustments godoc
+// @Summary 		List stock adjustments
+// @Description List the manual stock adjustments of a product, newest first
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Success 		200 	{array}		entity.StockAdjustment
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/stock/adjustments [get]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) GetAdjustments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	_, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	adjustments, err := h.StockDB.FindAdjustments(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if adjustments == nil {
+		adjustments = []entity.StockAdjustment{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(adjustments)
+}
+
+// Create stock adjustment godoc
+// @Summary 		Adjust stock
+// @Description Add (positive delta) or remove (negative delta) stock, optionally at a warehouse. Stock never goes below zero, overall or in the warehouse.
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			request		body			dto.StockAdjustmentInput true "adjustment"
+// @Success 		201 			{object}	entity.StockAdjustment
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		409 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id}/stock/adjustments [post]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) CreateAdjustment(w http.ResponseWriter, r *http.Request) {
+	var input dto.StockAdjustmentInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	productID, err := pkgEntity.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, r, entity.ErrInvalidID)
+		return
+	}
+	var warehouseID *pkgEntity.ID
+	if input.WarehouseID != nil {
+		id, _ := pkgEntity.ParseID(*input.WarehouseID)
+		warehouseID = &id
+	}
+	adjustment, err := entity.NewStockAdjustment(productID, warehouseID, input.Delta, input.Reason, input.Note, actorID(r))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.StockDB.Adjust(adjustment)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(adjustment)
+}
+
+// Get availability godoc
+// @Summary 		Get product availability
+// @Description Get the product's stock in each warehouse along with the totals
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Success 		200 	{object}	dto.AvailabilityOutput
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/availability [get]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	product, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	reserved, err := h.StockDB.Reserved(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	locations, err := h.StockDB.FindLocations(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	output := dto.AvailabilityOutput{
+		ProductID: id,
+		Available: product.Stock,
+		Reserved:  reserved,
+		Locations: []entity.StockLocation{},
+	}
+	for _, location := range locations {
+		output.OnHand += location.Quantity
+		output.Locations = append(output.Locations, location)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(output)
+}
+
+// Create stock transfer godoc
+// @Summary 		Transfer stock
+// @Description Move stock of a product from one warehouse to another, recorded as a pair of movements sharing a transfer_id
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			request		body			dto.StockTransferInput true "transfer"
+// @Success 		201 			{array}		entity.StockMovement
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		409 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id}/stock/transfers [post]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	var input dto.StockTransferInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	productID, err := pkgEntity.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, r, entity.ErrInvalidID)
+		return
+	}
+	from, _ := pkgEntity.ParseID(input.FromWarehouseID)
+	to, _ := pkgEntity.ParseID(input.ToWarehouseID)
+	movements, err := entity.NewStockTransfer(productID, from, to, input.Quantity, actorID(r))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.StockDB.Transfer(movements)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(movements)
+}
+
+// Get stock movements godoc
+// @Summary 		List stock movements
+// @Description List the warehouse movements of a product, newest first
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Success 		200 	{array}		entity.StockMovement
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/stock/movements [get]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) GetMovements(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	_, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	movements, err := h.StockDB.FindMovements(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if movements == nil {
+		movements = []entity.StockMovement{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(movements)
+}
+
+// Create reservation godoc
+// @Summary 		Reserve stock
+// @Description Hold quantity out of the available stock until the reservation is committed, released or expires
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			request		body			dto.ReservationInput true "reservation"
+// @Success 		201 			{object}	entity.Reservation
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		409 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id}/reservations [post]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	var input dto.ReservationInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	productID, err := pkgEntity.ParseID(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, r, entity.ErrInvalidID)
+		return
+	}
+	reservation, err := entity.NewReservation(productID, input.Quantity, input.TTL(), actorID(r))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.StockDB.Reserve(reservation)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// Get reservation godoc
+// @Summary 		Get reservation
+// @Description Get a reservation. Only its creator, editors and admins can see it.
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"reservation ID" Format(uuid)
+// @Success 		200 	{object}	entity.Reservation
+// @Failure 		403 	{object}	Problem
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/reservations/{id} [get]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) GetReservation(w http.ResponseWriter, r *http.Request) {
+	reservation, ok := h.reservation(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// Commit reservation godoc
+// @Summary 		Commit reservation
+// @Description Make a held reservation final; its quantity does not return to stock
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"reservation ID" Format(uuid)
+// @Success 		200 	{object}	entity.Reservation
+// @Failure 		403 	{object}	Problem
+// @Failure 		404 	{object}	Problem
+// @Failure 		409 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/reservations/{id}/commit [post]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) CommitReservation(w http.ResponseWriter, r *http.Request) {
+	h.finishReservation(w, r, h.StockDB.Commit)
+}
+
+// Release reservation godoc
+// @Summary 		Release reservation
+// @Description Return a held reservation's quantity to stock
+// @Tags 				stock
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"reservation ID" Format(uuid)
+// @Success 		200 	{object}	entity.Reservation
+// @Failure 		403 	{object}	Problem
+// @Failure 		404 	{object}	Problem
+// @Failure 		409 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/reservations/{id}/release [post]
+// @Security 		ApiKeyAuth
+func (h *StockHandler) ReleaseReservation(w http.ResponseWriter, r *http.Request) {
+	h.finishReservation(w, r, h.StockDB.Release)
+}
+
+func (h *StockHandler) finishReservation(w http.ResponseWriter, r *http.Request, finish func(id string) error) {
+	reservation, ok := h.reservation(w, r)
+	if !ok {
+		return
+	}
+	err := finish(reservation.ID.String())
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	reservation, err = h.StockDB.FindReservation(reservation.ID.String())
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+// reservation loads the reservation in the URL, writing the error response
+// when it does not exist or the caller may not act on it.
+func (h *StockHandler) reservation(w http.ResponseWriter, r *http.Request) (*entity.Reservation, bool) {
+	reservation, err := h.StockDB.FindReservation(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, r, err)
+		return nil, false
+	}
+	userID, role := currentUser(r)
+	ownReservation := reservation.CreatedBy != nil && reservation.CreatedBy.String() == userID
+	if !ownReservation && role != entity.RoleEditor && role != entity.RoleAdmin {
+		WriteProblem(w, r, http.StatusForbidden, "forbidden", "only the creator can act on this reservation")
+		return nil, false
+	}
+	return reservation, true
+}
+
+func actorID(r *http.Request) *pkgEntity.ID {
+	userID, _ := currentUser(r)
+	id, err := pkgEntity.ParseID(userID)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
@@ -2,26 +2,30 @@ package handlers
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
 
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
 	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
 	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/go-chi/chi"
 	"github.com/go-chi/jwtauth"
 )
 
-type Error struct {
-	Message string `json:"message"`
-}
-
 type UserHandler struct {
-	UserDB database.UserInterface
+	UserDB         database.UserInterface
+	RefreshTokenDB database.RefreshTokenInterface
+	RevokedTokenDB database.RevokedTokenInterface
+	Store          database.StoreInterface
 }
 
-func NewUserHandler(db database.UserInterface) *UserHandler {
+func NewUserHandler(db database.UserInterface, refreshTokenDB database.RefreshTokenInterface, revokedTokenDB database.RevokedTokenInterface, store database.StoreInterface) *UserHandler {
 	return &UserHandler{
-		UserDB: db,
+		UserDB:         db,
+		RefreshTokenDB: refreshTokenDB,
+		RevokedTokenDB: revokedTokenDB,
+		Store:          store,
 	}
 }
 
@@ -33,51 +37,177 @@ func NewUserHandler(db database.UserInterface) *UserHandler {
 // @Produce  		json
 // @Param 			request		body			dto.GetJWTInput true "user credentials"
 // @Success 		200 			{object}	dto.GetJWTOutput
-// @Failure 		400 			{object}	Error
-// @Failure 		401 			{object}	Error
-// @Failure 		404 			{object}	Error
-// @Failure 		500 			{object}	Error
+// @Failure 		400 			{object}	Problem
+// @Failure 		401 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
 // @Router 			/users/auth [post]
 func (h *UserHandler) GetJWTHandler(w http.ResponseWriter, r *http.Request) {
 	jwt := r.Context().Value("jwt").(*jwtauth.JWTAuth)
 	jwtExpiration := r.Context().Value("jwtExpiration").(int)
+	refreshExpiration := r.Context().Value("refreshExpiration").(int)
 	var input dto.GetJWTInput
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
 		return
 	}
 	user, err := h.UserDB.FindByEmail(input.Email)
+	if err != nil || !user.ComparePassword(input.Password) {
+		WriteError(w, r, entity.ErrInvalidCredentials)
+		return
+	}
+	token, err := pkgEntity.GenerateJWT(user.ID.String(), user.Role, jwt, jwtExpiration)
 	if err != nil {
-		w.WriteHeader(http.StatusNotFound)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
 		return
 	}
-	if !user.ComparePassword(input.Password) {
-		w.WriteHeader(http.StatusUnauthorized)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+
+	refreshToken, plainRefreshToken, err := entity.NewRefreshToken(user.ID, pkgEntity.ID{}, refreshExpiration)
+	if err != nil {
+		WriteError(w, r, err)
 		return
 	}
-	token, err := pkgEntity.GenerateJWT(user.ID.String(), jwt, jwtExpiration)
+	err = h.RefreshTokenDB.Create(refreshToken)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
 		return
 	}
 
 	accessToken := dto.GetJWTOutput{
-		AccessToken: token,
+		AccessToken:  token,
+		RefreshToken: plainRefreshToken,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(accessToken)
 }
 
+// RefreshJWT godoc
+// @Summary 		Refresh user JWT
+// @Description Exchange a refresh token for a new access token and a rotated refresh token. Replaying a refresh token that was already rotated revokes every token of its family.
+// @Tags 				users
+// @Accept  		json
+// @Produce  		json
+// @Param 			request		body			dto.RefreshJWTInput true "refresh token"
+// @Success 		200 			{object}	dto.GetJWTOutput
+// @Failure 		400 			{object}	Problem
+// @Failure 		401 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/users/auth/refresh [post]
+func (h *UserHandler) RefreshJWTHandler(w http.ResponseWriter, r *http.Request) {
+	jwt := r.Context().Value("jwt").(*jwtauth.JWTAuth)
+	jwtExpiration := r.Context().Value("jwtExpiration").(int)
+	refreshExpiration := r.Context().Value("refreshExpiration").(int)
+	var input dto.RefreshJWTInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil || input.RefreshToken == "" {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", "refresh_token is required")
+		return
+	}
+	current, err := h.RefreshTokenDB.FindByHash(pkgEntity.HashToken(input.RefreshToken))
+	if err != nil {
+		WriteError(w, r, entity.ErrRefreshTokenInvalid)
+		return
+	}
+	err = current.Validate()
+	if err == entity.ErrRefreshTokenReused {
+		if revokeErr := h.RefreshTokenDB.RevokeFamily(current.FamilyID.String()); revokeErr != nil {
+			WriteError(w, r, revokeErr)
+			return
+		}
+	}
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	user, err := h.UserDB.FindByID(current.UserID.String())
+	if err != nil {
+		WriteError(w, r, entity.ErrRefreshTokenInvalid)
+		return
+	}
+	next, plainRefreshToken, err := entity.NewRefreshToken(current.UserID, current.FamilyID, refreshExpiration)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.RefreshTokenDB.Rotate(current, next)
+	if err == entity.ErrRefreshTokenReused {
+		if revokeErr := h.RefreshTokenDB.RevokeFamily(current.FamilyID.String()); revokeErr != nil {
+			WriteError(w, r, revokeErr)
+			return
+		}
+		WriteError(w, r, err)
+		return
+	}
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	token, err := pkgEntity.GenerateJWT(user.ID.String(), user.Role, jwt, jwtExpiration)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+
+	output := dto.GetJWTOutput{
+		AccessToken:  token,
+		RefreshToken: plainRefreshToken,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(output)
+}
+
+// Logout godoc
+// @Summary 		Logout
+// @Description Revoke the access token used in the request. When a refresh token is sent, its whole family is revoked too.
+// @Tags 				users
+// @Accept  		json
+// @Produce  		json
+// @Param 			request		body			dto.LogoutInput false "refresh token to revoke"
+// @Success 		204
+// @Failure 		400 			{object}	Problem
+// @Failure 		401 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/users/logout [post]
+// @Security 		ApiKeyAuth
+func (h *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	token, _, err := jwtauth.FromContext(r.Context())
+	if err != nil || token == nil {
+		WriteProblem(w, r, http.StatusUnauthorized, "unauthorized", "missing or invalid access token")
+		return
+	}
+	var input dto.LogoutInput
+	err = json.NewDecoder(r.Body).Decode(&input)
+	if err != nil && err != io.EOF {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if token.JwtID() != "" {
+		err = h.RevokedTokenDB.Revoke(token.JwtID(), token.Expiration())
+		if err != nil {
+			WriteError(w, r, err)
+			return
+		}
+	}
+	if input.RefreshToken != "" {
+		refreshToken, err := h.RefreshTokenDB.FindByHash(pkgEntity.HashToken(input.RefreshToken))
+		if err == nil && refreshToken.UserID.String() == token.Subject() {
+			err = h.RefreshTokenDB.RevokeFamily(refreshToken.FamilyID.String())
+			if err != nil {
+				WriteError(w, r, err)
+				return
+			}
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Create user godoc
 // @Summary 		Create user
 // @Description Create user
@@ -86,31 +216,80 @@ func (h *UserHandler) GetJWTHandler(w http.ResponseWriter, r *http.Request) {
 // @Produce  		json
 // @Param 			request 		body			dto.CreateUserInput true "user request"
 // @Success 		201
-// @Failure 		400 				{object}	Error
-// @Failure 		500 				{object}	Error
+// @Failure 		400 				{object}	Problem
+// @Failure 		409 				{object}	Problem
+// @Failure 		422 				{object}	Problem
+// @Failure 		500 				{object}	Problem
 // @Router 			/users [post]
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var input dto.CreateUserInput
 	err := json.NewDecoder(r.Body).Decode(&input)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
 		return
 	}
 	user, err := entity.NewUser(input.Name, input.Email, input.Password)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
 		return
 	}
-	err = h.UserDB.Create(user)
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.UserDB.Create(user); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionCreate, auditEntityUser, user.ID.String(), nil, user)
+	})
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		error := Error{Message: err.Error()}
-		json.NewEncoder(w).Encode(error)
+		WriteError(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
 }
+
+// Update user role godoc
+// @Summary 		Update user role
+// @Description Change the role of a user. Admin only.
+// @Tags 				users
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"user ID" Format(uuid)
+// @Param 			request		body			dto.UpdateUserRoleInput true "role"
+// @Success 		200
+// @Failure 		400 			{object}	Problem
+// @Failure 		403 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Router 			/users/{id}/role [put]
+// @Security 		ApiKeyAuth
+func (h *UserHandler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var input dto.UpdateUserRoleInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if !entity.IsValidRole(input.Role) {
+		WriteError(w, r, entity.ErrInvalidRole)
+		return
+	}
+	before, err := h.UserDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	after := *before
+	after.Role = input.Role
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.UserDB.UpdateRole(id, input.Role); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityUser, id, before, &after)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/go-chi/chi"
+)
+
+type VariantHandler struct {
+	VariantDB database.VariantInterface
+	ProductDB database.ProductInterface
+	Store     database.StoreInterface
+}
+
+func NewVariantHandler(db database.VariantInterface, productDB database.ProductInterface, store database.StoreInterface) *VariantHandler {
+	return &VariantHandler{
+		VariantDB: db,
+		ProductDB: productDB,
+		Store:     store,
+	}
+}
+
+// Get variants godoc
+// @Summary 		List product variants
+// @Description List the variants of a product ordered by SKU
+// @Tags 				variants
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"product ID" Format(uuid)
+// @Success 		200 	{array}		dto.VariantOutput
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/products/{id}/variants [get]
+// @Security 		ApiKeyAuth
+func (h *VariantHandler) GetVariants(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	product, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	variants, err := h.VariantDB.FindByProduct(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	outputs := make([]dto.VariantOutput, len(variants))
+	for i, variant := range variants {
+		outputs[i] = dto.NewVariantOutput(variant, product)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(outputs)
+}
+
+// Get variant godoc
+// @Summary 		Get product variant
+// @Description Get product variant
+// @Tags 				variants
+// @Accept  		json
+// @Produce  		json
+// @Param 			id					path			string	true	"product ID" Format(uuid)
+// @Param 			variantID		path			string	true	"variant ID" Format(uuid)
+// @Success 		200 				{object}	dto.VariantOutput
+// @Failure 		404 				{object}	Problem
+// @Failure 		500 				{object}	Problem
+// @Router 			/products/{id}/variants/{variantID} [get]
+// @Security 		ApiKeyAuth
+func (h *VariantHandler) GetVariant(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	product, err := h.ProductDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	variant, err := h.VariantDB.FindByID(id, chi.URLParam(r, "variantID"))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.NewVariantOutput(*variant, product))
+}
+
+// Create variant godoc
+// @Summary 		Create product variant
+// @Description Add a variant to a product. SKUs are stored upper-case and are unique across products; two variants of a product cannot have the same options. Editors can only change their own products.
+// @Tags 				variants
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"product ID" Format(uuid)
+// @Param 			request		body			dto.VariantInput true "variant request"
+// @Success 		201 			{object}	dto.VariantOutput
+// @Failure 		400 			{object}	Problem
+// @Failure 		403 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		409 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/products/{id}/variants [post]
+// @Security 		ApiKeyAuth
+func (h *VariantHandler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	var input dto.VariantInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	product, ok := ownedProduct(w, r, h.ProductDB)
+	if !ok {
+		return
+	}
+	variant, err := entity.NewVariant(product.ID, input.SKU, input.Options, variantPrice(input))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.VariantDB.Create(variant); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionCreate, auditEntityVariant, variant.ID.String(), nil, variant)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.NewVariantOutput(*variant, product))
+}
+
+// Update variant godoc
+// @Summary 		Update product variant
+// @Description Replace the SKU, options and price of a variant. Omitting the price makes the variant sell for the product's price.
+// @Tags 				variants
+// @Accept  		json
+// @Produce  		json
+// @Param 			id					path			string	true	"product ID" Format(uuid)
+// @Param 			variantID		path			string	true	"variant ID" Format(uuid)
+// @Param 			request			body			dto.VariantInput true "variant request"
+// @Success 		200 				{object}	dto.VariantOutput
+// @Failure 		400 				{object}	Problem
+// @Failure 		403 				{object}	Problem
+// @Failure 		404 				{object}	Problem
+// @Failure 		409 				{object}	Problem
+// @Failure 		500 				{object}	Problem
+// @Router 			/products/{id}/variants/{variantID} [put]
+// @Security 		ApiKeyAuth
+func (h *VariantHandler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	var input dto.VariantInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	product, ok := ownedProduct(w, r, h.ProductDB)
+	if !ok {
+		return
+	}
+	before, err := h.VariantDB.FindByID(product.ID.String(), chi.URLParam(r, "variantID"))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	variant := *before
+	variant.SKU = entity.NormalizeSKU(input.SKU)
+	variant.Options = entity.NormalizeVariantOptions(input.Options)
+	variant.Price = variantPrice(input)
+	if err := variant.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.VariantDB.Update(&variant); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityVariant, variant.ID.String(), before, &variant)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.NewVariantOutput(variant, product))
+}
+
+// Delete variant godoc
+// @Summary 		Delete product variant
+// @Description Delete product variant
+// @Tags 				variants
+// @Accept  		json
+// @Produce  		json
+// @Param 			id					path			string	true	"product ID" Format(uuid)
+// @Param 			variantID		path			string	true	"variant ID" Format(uuid)
+// @Success 		200
+// @Failure 		403 				{object}	Problem
+// @Failure 		404 				{object}	Problem
+// @Failure 		500 				{object}	Problem
+// @Router 			/products/{id}/variants/{variantID} [delete]
+// @Security 		ApiKeyAuth
+func (h *VariantHandler) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	product, ok := ownedProduct(w, r, h.ProductDB)
+	if !ok {
+		return
+	}
+	variant, err := h.VariantDB.FindByID(product.ID.String(), chi.URLParam(r, "variantID"))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.VariantDB.Delete(product.ID.String(), variant.ID.String()); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionDelete, auditEntityVariant, variant.ID.String(), variant, nil)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+}
+
+// variantPrice is the validated price override of input, if any.
+func variantPrice(input dto.VariantInput) *pkgEntity.Money {
+	if input.Price == nil {
+		return nil
+	}
+	price, _ := input.Price.Money()
+	return &price
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/dto"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/go-chi/chi"
+)
+
+type WarehouseHandler struct {
+	WarehouseDB database.WarehouseInterface
+	Store       database.StoreInterface
+}
+
+func NewWarehouseHandler(db database.WarehouseInterface, store database.StoreInterface) *WarehouseHandler {
+	return &WarehouseHandler{
+		WarehouseDB: db,
+		Store:       store,
+	}
+}
+
+// Get warehouses godoc
+// @Summary 		List warehouses
+// @Description List warehouses ordered by code
+// @Tags 				warehouses
+// @Accept  		json
+// @Produce  		json
+// @Success 		200 	{array}		entity.Warehouse
+// @Failure 		500 	{object}	Problem
+// @Router 			/warehouses [get]
+// @Security 		ApiKeyAuth
+func (h *WarehouseHandler) GetWarehouses(w http.ResponseWriter, r *http.Request) {
+	warehouses, err := h.WarehouseDB.FindAll()
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	if warehouses == nil {
+		warehouses = []entity.Warehouse{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(warehouses)
+}
+
+// Get warehouse godoc
+// @Summary 		Get warehouse
+// @Description Get warehouse
+// @Tags 				warehouses
+// @Accept  		json
+// @Produce  		json
+// @Param 			id		path			string	true	"warehouse ID" Format(uuid)
+// @Success 		200 	{object}	entity.Warehouse
+// @Failure 		404 	{object}	Problem
+// @Failure 		500 	{object}	Problem
+// @Router 			/warehouses/{id} [get]
+// @Security 		ApiKeyAuth
+func (h *WarehouseHandler) GetWarehouse(w http.ResponseWriter, r *http.Request) {
+	warehouse, err := h.WarehouseDB.FindByID(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(warehouse)
+}
+
+// Create warehouse godoc
+// @Summary 		Create warehouse
+// @Description Create a warehouse. Codes are stored upper-case and must be unique. Admin only.
+// @Tags 				warehouses
+// @Accept  		json
+// @Produce  		json
+// @Param 			request		body			dto.WarehouseInput true "warehouse request"
+// @Success 		201 			{object}	entity.Warehouse
+// @Failure 		400 			{object}	Problem
+// @Failure 		409 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/warehouses [post]
+// @Security 		ApiKeyAuth
+func (h *WarehouseHandler) CreateWarehouse(w http.ResponseWriter, r *http.Request) {
+	var input dto.WarehouseInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	warehouse, err := entity.NewWarehouse(input.Code, input.Name)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.WarehouseDB.Create(warehouse); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionCreate, auditEntityWarehouse, warehouse.ID.String(), nil, warehouse)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(warehouse)
+}
+
+// Update warehouse godoc
+// @Summary 		Update warehouse
+// @Description Change a warehouse's code or name. Admin only.
+// @Tags 				warehouses
+// @Accept  		json
+// @Produce  		json
+// @Param 			id				path			string	true	"warehouse ID" Format(uuid)
+// @Param 			request		body			dto.WarehouseInput true "warehouse request"
+// @Success 		200 			{object}	entity.Warehouse
+// @Failure 		400 			{object}	Problem
+// @Failure 		404 			{object}	Problem
+// @Failure 		409 			{object}	Problem
+// @Failure 		422 			{object}	Problem
+// @Failure 		500 			{object}	Problem
+// @Router 			/warehouses/{id} [put]
+// @Security 		ApiKeyAuth
+func (h *WarehouseHandler) UpdateWarehouse(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	var input dto.WarehouseInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		WriteProblem(w, r, http.StatusBadRequest, "malformed_request", err.Error())
+		return
+	}
+	if err := input.Validate(); err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	before, err := h.WarehouseDB.FindByID(id)
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	warehouse := *before
+	warehouse.Code = strings.ToUpper(input.Code)
+	warehouse.Name = input.Name
+	err = h.Store.Transaction(func(tx database.Repositories) error {
+		if err := tx.WarehouseDB.Update(&warehouse); err != nil {
+			return err
+		}
+		return recordAudit(tx.AuditDB, r, entity.AuditActionUpdate, auditEntityWarehouse, id, before, &warehouse)
+	})
+	if err != nil {
+		WriteError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(warehouse)
+}
@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/webserver/handlers"
+	"github.com/go-chi/jwtauth"
+	"github.com/lestrrat-go/jwx/jwt"
+)
+
+// Authenticator replaces jwtauth.Authenticator so that missing or invalid
+// tokens are answered with a problem instead of a plain text body.
+func Authenticator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _, err := jwtauth.FromContext(r.Context())
+		if err != nil || token == nil || jwt.Validate(token) != nil {
+			handlers.WriteProblem(w, r, http.StatusUnauthorized, "unauthorized", "missing or invalid access token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/webserver/handlers"
+	"github.com/go-chi/jwtauth"
+)
+
+// RequireRole must run after jwtauth.Verifier. It responds 403 unless the
+// token's role claim is one of roles.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, claims, _ := jwtauth.FromContext(r.Context())
+			role, _ := claims["role"].(string)
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			handlers.WriteProblem(w, r, http.StatusForbidden, "forbidden", "your role is not allowed to perform this action")
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/go-chi/jwtauth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
+	handler := jwtauth.Verifier(tokenAuth)(jwtauth.Authenticator(RequireRole(entity.RoleEditor, entity.RoleAdmin)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)))
+
+	tests := []struct {
+		role string
+		code int
+	}{
+		{entity.RoleAdmin, http.StatusOK},
+		{entity.RoleEditor, http.StatusOK},
+		{entity.RoleViewer, http.StatusForbidden},
+		{"", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		token, err := pkgEntity.GenerateJWT(pkgEntity.NewID().String(), tt.role, tokenAuth, 60)
+		assert.Nil(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/products", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, tt.code, rec.Code, tt.role)
+	}
+}
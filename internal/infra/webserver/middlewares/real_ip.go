@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseTrustedProxies parses a comma-separated list of IP addresses and
+// CIDR blocks. An empty list trusts no proxy.
+func ParseTrustedProxies(list string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			entry = fmt.Sprintf("%s/%d", entry, bits)
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// RealIP replaces r.RemoteAddr with the client address reported by a proxy,
+// but only when the connection comes from one of trusted; otherwise the
+// headers are ignored so that clients cannot choose their own address.
+// X-Forwarded-For is read right to left, skipping trusted hops, since
+// entries to the left of the last proxy are whatever the client sent.
+func RealIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	isTrusted := func(addr string) bool {
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return false
+		}
+		for _, network := range trusted {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				peer = r.RemoteAddr
+			}
+			if !isTrusted(peer) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			client := ""
+			if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+				hops := strings.Split(strings.Join(forwarded, ","), ",")
+				for i := len(hops) - 1; i >= 0; i-- {
+					hop := strings.TrimSpace(hops[i])
+					if net.ParseIP(hop) == nil {
+						break
+					}
+					client = hop
+					if !isTrusted(hop) {
+						break
+					}
+				}
+			} else if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+				client = realIP
+			}
+			if client != "" {
+				r.RemoteAddr = net.JoinHostPort(client, "0")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	assert.Nil(t, err)
+	var remoteAddr string
+	handler := RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{"untrusted peer", "203.0.113.7:4000", "198.51.100.1", "198.51.100.2", "203.0.113.7:4000"},
+		{"trusted peer", "10.0.0.2:4000", "198.51.100.1", "", "198.51.100.1:0"},
+		{"forged left entries", "10.0.0.2:4000", "1.2.3.4, 198.51.100.1, 10.0.0.3", "", "198.51.100.1:0"},
+		{"real ip header", "192.168.1.1:4000", "", "198.51.100.2", "198.51.100.2:0"},
+		{"garbage header", "10.0.0.2:4000", "not-an-ip", "", "10.0.0.2:4000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			assert.Equal(t, tt.want, remoteAddr)
+		})
+	}
+
+	_, err = ParseTrustedProxies("10.0.0.0/8,nope")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/database"
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/infra/webserver/handlers"
+	"github.com/go-chi/jwtauth"
+)
+
+// RejectRevoked must run after jwtauth.Verifier. It responds 401 when the
+// verified token's jti is on the denylist; tokens without a jti are let
+// through since they cannot have been revoked.
+func RejectRevoked(store database.RevokedTokenInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, _, err := jwtauth.FromContext(r.Context())
+			if err != nil || token == nil || token.JwtID() == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			revoked, err := store.IsRevoked(token.JwtID())
+			if err != nil {
+				handlers.WriteError(w, r, err)
+				return
+			}
+			if revoked {
+				handlers.WriteProblem(w, r, http.StatusUnauthorized, "token_revoked", "token revoked")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
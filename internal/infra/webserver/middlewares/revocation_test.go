@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/felipedias-dev/fullcycle-go-expert-basic-api/internal/entity"
+	pkgEntity "github.com/felipedias-dev/fullcycle-go-expert-basic-api/pkg/entity"
+	"github.com/go-chi/jwtauth"
+	"github.com/stretchr/testify/assert"
+)
+
+type revokedTokenStoreStub map[string]bool
+
+func (s revokedTokenStoreStub) Revoke(jti string, expiresAt time.Time) error {
+	s[jti] = true
+	return nil
+}
+
+func (s revokedTokenStoreStub) IsRevoked(jti string) (bool, error) {
+	return s[jti], nil
+}
+
+func TestRejectRevoked(t *testing.T) {
+	tokenAuth := jwtauth.New("HS256", []byte("secret"), nil)
+	store := revokedTokenStoreStub{}
+	handler := jwtauth.Verifier(tokenAuth)(jwtauth.Authenticator(RejectRevoked(store)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)))
+
+	tokenString, err := pkgEntity.GenerateJWT(pkgEntity.NewID().String(), entity.RoleViewer, tokenAuth, 60)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/products", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	token, err := tokenAuth.Decode(tokenString)
+	assert.Nil(t, err)
+	store.Revoke(token.JwtID(), token.Expiration())
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "token revoked")
+}
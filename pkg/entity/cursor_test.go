@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	secret := []byte("secret")
+	cursor := Cursor{CreatedAt: time.Date(2023, 4, 1, 12, 30, 0, 123456789, time.FixedZone("", -3*3600)), ID: NewID(),
+		SortDir: "desc", Filter: "filters"}
+
+	token := EncodeCursor(cursor, secret)
+	decoded, err := DecodeCursor(token, secret)
+	assert.Nil(t, err)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.CreatedAt.Format(time.RFC3339Nano), decoded.CreatedAt.Format(time.RFC3339Nano))
+	assert.Equal(t, "desc", decoded.SortDir)
+	assert.Equal(t, "filters", decoded.Filter)
+}
+
+func TestCursor_Invalid(t *testing.T) {
+	secret := []byte("secret")
+	token := EncodeCursor(Cursor{CreatedAt: time.Now(), ID: NewID()}, secret)
+
+	_, err := DecodeCursor(token, []byte("other"))
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	forged := EncodeCursor(Cursor{CreatedAt: time.Now(), ID: NewID()}, []byte("other"))
+	_, err = DecodeCursor(forged, secret)
+	assert.Equal(t, ErrInvalidCursor, err)
+
+	_, err = DecodeCursor("garbage", secret)
+	assert.Equal(t, ErrInvalidCursor, err)
+}
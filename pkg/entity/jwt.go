@@ -6,10 +6,12 @@ import (
 	"github.com/go-chi/jwtauth"
 )
 
-func GenerateJWT(id string, jwt *jwtauth.JWTAuth, expiration int) (string, error) {
+func GenerateJWT(id, role string, jwt *jwtauth.JWTAuth, expiration int) (string, error) {
 	_, token, err := jwt.Encode(map[string]interface{}{
-		"sub": id,
-		"exp": time.Now().Add(time.Second * time.Duration(expiration)).Unix(),
+		"sub":  id,
+		"jti":  NewID().String(),
+		"role": role,
+		"exp":  time.Now().Add(time.Second * time.Duration(expiration)).Unix(),
 	})
 	if err != nil {
 		return "", err
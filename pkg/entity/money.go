@@ -0,0 +1,174 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is assumed for amounts sent without a currency, such as
+// the integer prices older clients still send.
+const DefaultCurrency = "BRL"
+
+var (
+	ErrInvalidCurrency = errors.New("invalid currency")
+	ErrInvalidAmount   = errors.New("invalid amount")
+)
+
+// currencyExponents holds the number of minor-unit digits of the supported
+// ISO 4217 currencies.
+var currencyExponents = map[string]int{
+	"ARS": 2, "AUD": 2, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2,
+	"COP": 2, "EUR": 2, "GBP": 2, "JPY": 0, "KRW": 0, "KWD": 3, "MXN": 2,
+	"PYG": 0, "USD": 2, "UYU": 2,
+}
+
+// Money is an amount in the minor units of an ISO 4217 currency, so 1234
+// BRL is R$ 12,34.
+type Money struct {
+	Amount   int64  `json:"amount" swaggertype:"string" example:"12.34"`
+	Currency string `json:"currency" example:"BRL"`
+}
+
+func IsValidCurrency(currency string) bool {
+	_, ok := currencyExponents[currency]
+	return ok
+}
+
+// CurrencyExponent returns the number of decimal digits of currency, or -1
+// when it is not supported.
+func CurrencyExponent(currency string) int {
+	exponent, ok := currencyExponents[currency]
+	if !ok {
+		return -1
+	}
+	return exponent
+}
+
+func NewMoney(amount int64, currency string) (Money, error) {
+	if !IsValidCurrency(currency) {
+		return Money{}, ErrInvalidCurrency
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// ParseMoney reads a decimal amount such as "12.34" in currency. Amounts
+// with more decimal digits than the currency has are rejected instead of
+// rounded.
+func ParseMoney(amount, currency string) (Money, error) {
+	exponent := CurrencyExponent(currency)
+	if exponent < 0 {
+		return Money{}, ErrInvalidCurrency
+	}
+	whole, fraction, _ := strings.Cut(amount, ".")
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+	if whole == "" || !isDigits(whole) || !isDigits(fraction) || len(fraction) > exponent ||
+		(strings.Contains(amount, ".") && fraction == "") {
+		return Money{}, ErrInvalidAmount
+	}
+	digits := whole + fraction + strings.Repeat("0", exponent-len(fraction))
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, ErrInvalidAmount
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal formats the amount in major units, e.g. "12.34".
+func (m Money) Decimal() string {
+	exponent := CurrencyExponent(m.Currency)
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	digits := strconv.FormatInt(amount, 10)
+	if exponent <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Convert returns m in currency, where rate is how many major units of
+// currency one major unit of m.Currency buys. The result is rounded half away
+// from zero to the precision of currency.
+func (m Money) Convert(currency string, rate *big.Rat) (Money, error) {
+	from, to := CurrencyExponent(m.Currency), CurrencyExponent(currency)
+	if from < 0 || to < 0 {
+		return Money{}, ErrInvalidCurrency
+	}
+	value := new(big.Rat).SetFrac(big.NewInt(m.Amount), pow10(from))
+	value.Mul(value, rate)
+	value.Mul(value, new(big.Rat).SetInt(pow10(to)))
+
+	num := new(big.Int).Abs(value.Num())
+	quo, rem := new(big.Int).QuoRem(num, value.Denom(), new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(value.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if value.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	if !quo.IsInt64() {
+		return Money{}, ErrInvalidAmount
+	}
+	return Money{Amount: quo.Int64(), Currency: currency}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string so clients never have
+// to deal with floating point.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw moneyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	money, err := ParseMoney(raw.Amount, raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = money
+	return nil
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		minor    int64
+		err      error
+	}{
+		{"12.34", "BRL", 1234, nil},
+		{"12.3", "BRL", 1230, nil},
+		{"12", "BRL", 1200, nil},
+		{"0.05", "USD", 5, nil},
+		{"-1.50", "EUR", -150, nil},
+		{"1500", "JPY", 1500, nil},
+		{"1.234", "KWD", 1234, nil},
+		{"12.345", "BRL", 0, ErrInvalidAmount},
+		{"1.5", "JPY", 0, ErrInvalidAmount},
+		{"12.", "BRL", 0, ErrInvalidAmount},
+		{".5", "BRL", 0, ErrInvalidAmount},
+		{"1e3", "BRL", 0, ErrInvalidAmount},
+		{"", "BRL", 0, ErrInvalidAmount},
+		{"99999999999999999999", "BRL", 0, ErrInvalidAmount},
+		{"12.34", "XXX", 0, ErrInvalidCurrency},
+		{"12.34", "brl", 0, ErrInvalidCurrency},
+	}
+	for _, tt := range tests {
+		money, err := ParseMoney(tt.amount, tt.currency)
+		assert.Equal(t, tt.err, err, tt.amount)
+		assert.Equal(t, tt.minor, money.Amount, tt.amount)
+	}
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	assert.Equal(t, "12.34", Money{1234, "BRL"}.Decimal())
+	assert.Equal(t, "0.05", Money{5, "USD"}.Decimal())
+	assert.Equal(t, "-1.50", Money{-150, "EUR"}.Decimal())
+	assert.Equal(t, "1500", Money{1500, "JPY"}.Decimal())
+	assert.Equal(t, "0.001", Money{1, "KWD"}.Decimal())
+	assert.Equal(t, "12.34 BRL", Money{1234, "BRL"}.String())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(Money{1234, "BRL"})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"amount":"12.34","currency":"BRL"}`, string(data))
+
+	var money Money
+	assert.Nil(t, json.Unmarshal(data, &money))
+	assert.Equal(t, Money{1234, "BRL"}, money)
+
+	err = json.Unmarshal([]byte(`{"amount":"12.34","currency":"ZZZ"}`), &money)
+	assert.Equal(t, ErrInvalidCurrency, err)
+}
+
+func TestMoney_Convert(t *testing.T) {
+	rate, _ := new(big.Rat).SetString("0.1834")
+	money, err := Money{1000, "BRL"}.Convert("USD", rate)
+	assert.Nil(t, err)
+	assert.Equal(t, Money{183, "USD"}, money)
+
+	rate, _ = new(big.Rat).SetString("151.5")
+	money, err = Money{999, "USD"}.Convert("JPY", rate)
+	assert.Nil(t, err)
+	assert.Equal(t, Money{1513, "JPY"}, money)
+
+	rate, _ = new(big.Rat).SetString("0.005")
+	money, err = Money{100, "JPY"}.Convert("USD", rate)
+	assert.Nil(t, err)
+	assert.Equal(t, Money{50, "USD"}, money)
+
+	_, err = Money{100, "USD"}.Convert("XYZ", rate)
+	assert.Equal(t, ErrInvalidCurrency, err)
+}
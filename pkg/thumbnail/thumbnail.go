@@ -0,0 +1,63 @@
+// Package thumbnail shrinks images with the standard library alone.
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// New scales img down so that its longest side is at most maxSide pixels,
+// averaging the source pixels that fall into each thumbnail pixel. Images
+// that already fit are only copied.
+func New(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	thumbWidth, thumbHeight := width, height
+	if width > maxSide || height > maxSide {
+		if width >= height {
+			thumbWidth, thumbHeight = maxSide, height*maxSide/width
+		} else {
+			thumbWidth, thumbHeight = width*maxSide/height, maxSide
+		}
+	}
+	if thumbWidth < 1 {
+		thumbWidth = 1
+	}
+	if thumbHeight < 1 {
+		thumbHeight = 1
+	}
+
+	thumb := image.NewRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		y0 := bounds.Min.Y + y*height/thumbHeight
+		y1 := bounds.Min.Y + (y+1)*height/thumbHeight
+		for x := 0; x < thumbWidth; x++ {
+			x0 := bounds.Min.X + x*width/thumbWidth
+			x1 := bounds.Min.X + (x+1)*width/thumbWidth
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			thumb.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n),
+			})
+		}
+	}
+	return thumb
+}
+
+// Encode writes img as a JPEG when format is "jpeg" and as a PNG, which
+// keeps transparency, otherwise. It returns the content type written.
+func Encode(w io.Writer, img image.Image, format string) (string, error) {
+	if format == "jpeg" {
+		return "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	}
+	return "image/png", png.Encode(w, img)
+}
@@ -0,0 +1,49 @@
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			if x < 200 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	thumb := New(img, 100)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, thumb.At(10, 10))
+	assert.Equal(t, color.RGBA{B: 255, A: 255}, thumb.At(90, 40))
+
+	tall := New(image.NewRGBA(image.Rect(0, 0, 10, 1000)), 100)
+	assert.Equal(t, image.Rect(0, 0, 1, 100), tall.Bounds())
+
+	small := New(image.NewRGBA(image.Rect(0, 0, 30, 20)), 100)
+	assert.Equal(t, image.Rect(0, 0, 30, 20), small.Bounds())
+}
+
+func TestEncode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	var buf bytes.Buffer
+	contentType, err := Encode(&buf, img, "png")
+	assert.Nil(t, err)
+	assert.Equal(t, "image/png", contentType)
+	_, err = png.Decode(&buf)
+	assert.Nil(t, err)
+
+	buf.Reset()
+	contentType, err = Encode(&buf, img, "jpeg")
+	assert.Nil(t, err)
+	assert.Equal(t, "image/jpeg", contentType)
+}